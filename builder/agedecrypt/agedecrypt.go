@@ -0,0 +1,76 @@
+// Package agedecrypt decifra parâmetros cifrados com age (https://age-encryption.org), para
+// equipes que preferem decifrar localmente ou fora da nuvem, sem depender do KMS.
+//
+//	decryptor, err := agedecrypt.NewFromKeys(os.Getenv("CONFIG_AGE_KEY"))
+//	data, err := svc.BuildConfigFromPrefixes(ctx, builder.BuildOptions{
+//		Prefixes:   []string{"/app"},
+//		Decryptors: []builder.Decryptor{decryptor},
+//	})
+package agedecrypt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// ErrNoIdentities indica que nenhuma identidade age foi informada
+var ErrNoIdentities = errors.New("nenhuma identidade age informada")
+
+// Decryptor decifra valores no formato ASCII armor do age ("-----BEGIN AGE ENCRYPTED
+// FILE-----"). Implementa builder.Decryptor.
+type Decryptor struct {
+	identities []age.Identity
+}
+
+// New cria um Decryptor com as identidades informadas
+func New(identities ...age.Identity) (*Decryptor, error) {
+	if len(identities) == 0 {
+		return nil, ErrNoIdentities
+	}
+	return &Decryptor{identities: identities}, nil
+}
+
+// NewFromKeys cria um Decryptor a partir de um texto com uma ou mais identidades
+// (AGE-SECRET-KEY-...), uma por linha, no formato dos arquivos de chave do age
+func NewFromKeys(keys string) (*Decryptor, error) {
+	identities, err := age.ParseIdentities(strings.NewReader(keys))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler as identidades age: %w", err)
+	}
+	return New(identities...)
+}
+
+// NewFromFile cria um Decryptor a partir de um arquivo de chaves do age
+func NewFromFile(path string) (*Decryptor, error) {
+	keys, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o arquivo de identidades age %s: %w", path, err)
+	}
+	return NewFromKeys(string(keys))
+}
+
+// Decrypt implementa builder.Decryptor. Valores que não estão no formato ASCII armor
+// do age são ignorados.
+func (d *Decryptor) Decrypt(ctx context.Context, name, value string) (string, bool, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, armor.Header) {
+		return "", false, nil
+	}
+
+	reader, err := age.Decrypt(armor.NewReader(strings.NewReader(value)), d.identities...)
+	if err != nil {
+		return "", true, fmt.Errorf("erro ao decifrar o valor age: %w", err)
+	}
+	plaintext, err := io.ReadAll(reader)
+	if err != nil {
+		return "", true, fmt.Errorf("erro ao decifrar o valor age: %w", err)
+	}
+	return string(plaintext), true, nil
+}
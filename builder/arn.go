@@ -0,0 +1,42 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parameterARN partes de um ARN de parâmetro do Parameter Store
+// (ex.: "arn:aws:ssm:us-east-1:123456789012:parameter/app/db")
+type parameterARN struct {
+	Region  string
+	Account string
+	Path    string
+}
+
+// parseParameterARN interpreta o ARN de um parâmetro ou hierarquia. Retorna false quando o
+// valor não é um ARN, e erro quando é um ARN que não identifica um parâmetro.
+func parseParameterARN(value string) (parameterARN, bool, error) {
+	if !strings.HasPrefix(value, "arn:") {
+		return parameterARN{}, false, nil
+	}
+
+	parts := strings.SplitN(value, ":", 6)
+	if len(parts) != 6 || parts[1] == "" || parts[2] != "ssm" {
+		return parameterARN{}, true, fmt.Errorf("ARN %q não pertence ao Parameter Store", value)
+	}
+	resource, ok := strings.CutPrefix(parts[5], "parameter")
+	if !ok || !strings.HasPrefix(resource, "/") {
+		return parameterARN{}, true, fmt.Errorf("ARN %q não identifica um parâmetro", value)
+	}
+
+	return parameterARN{Region: parts[3], Account: parts[4], Path: resource}, true, nil
+}
+
+// normalizePrefix converte um ARN de parâmetro no path correspondente; paths são mantidos
+func normalizePrefix(prefix string) (string, parameterARN) {
+	arn, ok, err := parseParameterARN(prefix)
+	if !ok || err != nil {
+		return prefix, parameterARN{}
+	}
+	return arn.Path, arn
+}
@@ -2,68 +2,410 @@ package builder
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 
-	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	"gopkg.in/yaml.v3"
 )
 
 // New cria uma nova instância do ConfigBuilder
-func New(ssmClient *ssm.Client) *ConfigBuilder {
+func New(ssmClient SSMAPI) *ConfigBuilder {
 	return &ConfigBuilder{
 		ssmClient: ssmClient,
 	}
 }
 
-// BuildConfigFromPrefixes constrói a configuração a partir dos prefixos
+// SetSSMClient substitui o cliente SSM utilizado pelas próximas construções
+func (b *ConfigBuilder) SetSSMClient(ssmClient SSMAPI) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ssmClient = ssmClient
+}
+
+// BuildConfigFromPrefixes constrói a configuração a partir dos prefixos.
+// No modo BestEffort, retorna o documento parcial junto de um *PartialError
+// listando os prefixos ignorados.
 func (b *ConfigBuilder) BuildConfigFromPrefixes(ctx context.Context, opts BuildOptions) ([]byte, error) {
-	if opts.YAMLRules {
-		// Modo YAML para regras
-		configMap := make(map[string]interface{})
+	return b.build(ctx, opts, func(ctx context.Context, prefix string) ([]types.Parameter, error) {
+		return b.fetchPrefix(ctx, prefix, opts)
+	})
+}
 
-		for _, prefix := range opts.Prefixes {
-			params, err := b.getParametersByPath(ctx, prefix)
-			if err != nil {
-				return nil, fmt.Errorf("erro ao buscar parâmetros do prefixo %s: %w", prefix, err)
+// BuildMap constrói a configuração e retorna o mapa montado, sem serializá-lo.
+// As opções de formato de saída são ignoradas; RootKey continua sendo aplicada.
+func (b *ConfigBuilder) BuildMap(ctx context.Context, opts BuildOptions) (map[string]interface{}, error) {
+	ctx, stats := b.withStats(ctx, opts)
+	asm, err := b.assemble(ctx, opts, func(ctx context.Context, prefix string) ([]types.Parameter, error) {
+		return b.fetchPrefix(ctx, prefix, opts)
+	})
+	if err != nil {
+		return nil, b.annotate(ctx, err)
+	}
+
+	output := b.wrapRootKey(asm.config, opts.RootKey)
+	if stats != nil {
+		opts.OnStats(stats.report(asm, nil))
+	}
+	if len(asm.skipped) > 0 {
+		return output, b.annotate(ctx, &PartialError{Skipped: asm.skipped})
+	}
+	return output, nil
+}
+
+// BuildMany gera várias saídas buscando uma única vez a raiz comum a todos os prefixos do
+// cliente padrão; prefixos com cliente próprio (Client, AWSProfile ou ARNClient) ou cujo
+// WithDecryption difere do da primeira saída são buscados individualmente. Os tempos
+// limite e o OnPage da busca compartilhada são os da primeira saída informada.
+func (b *ConfigBuilder) BuildMany(ctx context.Context, specs []OutputSpec) (map[string][]byte, error) {
+	if len(specs) == 0 {
+		return map[string][]byte{}, nil
+	}
+
+	var prefixes []string
+	shared := make(map[string]bool)
+	for _, spec := range specs {
+		for _, prefix := range b.resolvePrefixes(spec.Options) {
+			if prefix.client != nil || prefix.awsProfile != "" || prefix.arn.Path != "" {
+				continue
 			}
+			prefixes = append(prefixes, prefix.path)
+			shared[prefix.path] = true
+		}
+	}
 
-			prefixConfig, err := b.buildYAMLStructure(params, prefix, opts.StripPrefix)
-			if err != nil {
-				return nil, err
+	var params []types.Parameter
+	if len(prefixes) > 0 {
+		root := b.commonRoot(prefixes)
+		fetchOpts := BuildOptions{
+			PageTimeout:    specs[0].Options.PageTimeout,
+			FetchTimeout:   specs[0].Options.FetchTimeout,
+			OnPage:         specs[0].Options.OnPage,
+			WithDecryption: specs[0].Options.WithDecryption,
+		}
+		var err error
+		params, err = b.getParametersByPath(ctx, root, fetchOpts)
+		if err != nil {
+			return nil, b.annotate(ctx, fmt.Errorf("erro ao buscar parâmetros da raiz comum %s: %w", root, err))
+		}
+	}
+
+	outputs := make(map[string][]byte, len(specs))
+	for _, spec := range specs {
+		opts := spec.Options
+		data, err := b.build(ctx, opts, func(ctx context.Context, prefix string) ([]types.Parameter, error) {
+			if shared[prefix] && b.clientScope(ctx) == "" && opts.WithDecryption == specs[0].Options.WithDecryption {
+				return b.selectParameters(ctx, params, prefix, opts)
 			}
-			b.mergeMaps(configMap, prefixConfig)
+			return b.fetchPrefix(ctx, prefix, opts)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar a saída %s: %w", spec.Name, err)
 		}
+		outputs[spec.Name] = data
+	}
 
-		// Para YAML, não aplicamos ordenação por dependências (específica para schemas JSON)
-		return yaml.Marshal(configMap)
+	return outputs, nil
+}
+
+// BuildAll executa construções independentes simultaneamente (ex.: schema, regras e
+// feature flags) e retorna as saídas indexadas pelo mesmo nome das opções. Ao contrário de
+// BuildMany, cada construção busca seus próprios prefixos. As saídas bem-sucedidas são
+// retornadas mesmo quando outras falham, junto dos erros reunidos; use SetMaxConcurrency
+// para limitar as chamadas simultâneas ao SSM.
+func (b *ConfigBuilder) BuildAll(ctx context.Context, builds map[string]BuildOptions) (map[string][]byte, error) {
+	type result struct {
+		name string
+		data []byte
+		err  error
+	}
+
+	results := make(chan result, len(builds))
+	var wg sync.WaitGroup
+	for name, opts := range builds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := b.BuildConfigFromPrefixes(ctx, opts)
+			results <- result{name: name, data: data, err: err}
+		}()
 	}
+	wg.Wait()
+	close(results)
 
-	// Modo JSON padrão
-	configMap := make(map[string]interface{})
+	outputs := make(map[string][]byte, len(builds))
+	var failed []result
+	for r := range results {
+		if r.data != nil {
+			outputs[r.name] = r.data
+		}
+		if r.err != nil {
+			failed = append(failed, r)
+		}
+	}
 
-	for _, prefix := range opts.Prefixes {
-		params, err := b.getParametersByPath(ctx, prefix)
+	// Os erros são ordenados pelo nome para que a mensagem seja determinística
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].name < failed[j].name
+	})
+	errs := make([]error, 0, len(failed))
+	for _, r := range failed {
+		errs = append(errs, fmt.Errorf("erro ao gerar a saída %s: %w", r.name, r.err))
+	}
+	return outputs, errors.Join(errs...)
+}
+
+// build constrói a configuração obtendo os parâmetros de cada prefixo através de fetch
+func (b *ConfigBuilder) build(ctx context.Context, opts BuildOptions, fetch fetchFunc) ([]byte, error) {
+	ctx, stats := b.withStats(ctx, opts)
+	asm, err := b.assemble(ctx, opts, fetch)
+	if err != nil {
+		return nil, b.annotate(ctx, err)
+	}
+
+	data, err := b.render(ctx, asm, opts)
+	if err == nil && opts.Gzip {
+		data, err = b.compress(data)
+	}
+	if err == nil && stats != nil {
+		opts.OnStats(stats.report(asm, data))
+	}
+	data, err = b.partialResult(data, err, asm.skipped)
+	return data, b.annotate(ctx, err)
+}
+
+// assemble busca e mescla os parâmetros de todos os prefixos na árvore de configuração
+func (b *ConfigBuilder) assemble(ctx context.Context, opts BuildOptions, fetch fetchFunc) (*assembly, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	asm := newAssembly()
+	patches, err := b.assemblePrefixes(ctx, asm, opts, fetch)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.finishAssembly(ctx, asm, patches, opts); err != nil {
+		return nil, err
+	}
+	return asm, nil
+}
+
+// assemblePrefixes busca e mescla os prefixos das opções em asm, sem as etapas posteriores
+// à mescla, retornando os documentos JSON Patch encontrados
+func (b *ConfigBuilder) assemblePrefixes(ctx context.Context, asm *assembly, opts BuildOptions, fetch fetchFunc) ([]patchDocument, error) {
+	// Os erros de todos os prefixos são reunidos em vez de interromper no primeiro
+	var errs []error
+	var patches []patchDocument
+	for _, spec := range b.resolvePrefixes(opts) {
+		prefixCtx, err := b.withPrefixClient(ctx, spec, opts)
+		var prefixConfig map[string]interface{}
+		var prefixOwners map[string]string
+		var params []types.Parameter
+		var prefixPatches []patchDocument
+		if err == nil {
+			prefixConfig, prefixOwners, params, prefixPatches, err = b.assemblePrefix(prefixCtx, spec, opts, fetch)
+		}
 		if err != nil {
-			return nil, fmt.Errorf("erro ao buscar parâmetros do prefixo %s: %w", prefix, err)
+			if opts.BestEffort {
+				asm.skipped = append(asm.skipped, PrefixError{Prefix: spec.path, Err: err})
+			} else {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if opts.CaseInsensitiveKeys {
+			canonical := b.canonicalizer(opts.KeyCase, asm.keyForms)
+			prefixConfig, _ = b.rewriteKeys(prefixConfig, "", canonical)
+			prefixOwners = b.rewriteOwners(prefixOwners, canonical)
 		}
 
-		prefixConfig := b.buildStructure(params, prefix, opts.StripPrefix, opts.SortByDependencies)
-		b.mergeMaps(configMap, prefixConfig)
+		asm.addPrefix(prefixCtx, spec.path, params)
+		if err := b.checkDocumentSize(b.valuesSize(asm.params), opts.Limits); err != nil {
+			return nil, err
+		}
+		patches = append(patches, prefixPatches...)
+		mounted := b.mountConfig(prefixConfig, spec.key)
+		if stats := statsFrom(ctx); stats != nil {
+			stats.addConflicts(asm.config, mounted, "", opts.MergeStrategy)
+		}
+		b.merge(asm.config, mounted, opts.MergeStrategy)
+		for key, name := range prefixOwners {
+			if spec.key != "" {
+				key = spec.key + "/" + key
+			}
+			asm.owners[key] = name
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return patches, nil
+}
+
+// finishAssembly executa, uma única vez sobre a árvore já mesclada, as etapas posteriores
+// à mescla: remapeamentos, patches, condições, tipos, validações, ordenação e metadados
+func (b *ConfigBuilder) finishAssembly(ctx context.Context, asm *assembly, patches []patchDocument, opts BuildOptions) error {
+	if len(opts.KeyRemaps) > 0 {
+		if err := b.remapKeys(asm, opts.KeyRemaps, opts.CaseInsensitiveKeys); err != nil {
+			return err
+		}
 	}
 
-	if opts.SortByDependencies {
-		err := sortTypesByDependency(&configMap)
+	// Os documentos JSON Patch são aplicados após a mescla de todos os prefixos
+	if len(patches) > 0 {
+		config, err := b.applyPatches(asm.config, patches)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao ordenar tipos por dependência: %w", err)
+			return err
+		}
+		asm.config = config
+	}
+
+	if len(opts.Conditions) > 0 {
+		if err := b.applyConditions(ctx, asm, opts); err != nil {
+			return err
 		}
 	}
 
-	if opts.JSONOutput {
-		return json.MarshalIndent(configMap, "", "  ")
+	if len(opts.TypeSchema) > 0 {
+		config := &Config{data: asm.config, caseInsensitive: opts.CaseInsensitiveKeys}
+		if err := config.Coerce(opts.TypeSchema); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.Validators) > 0 {
+		if err := b.validate(ctx, asm.config, opts.Validators); err != nil {
+			return err
+		}
 	}
 
-	return json.Marshal(configMap)
+	// Para YAML, não aplicamos ordenação por dependências (específica para schemas JSON)
+	if !opts.YAMLRules && opts.SortByDependencies {
+		err := sortTypesByDependencies(asm.config)
+		if err != nil {
+			return fmt.Errorf("erro ao ordenar tipos por dependência: %w", err)
+		}
+	}
+
+	if opts.TagsMetadata {
+		if err := b.attachTagsMetadata(ctx, asm); err != nil {
+			return err
+		}
+	}
+
+	if opts.BuildMetadata {
+		asm.config[BuildKey] = b.buildMetadata(asm)
+	}
+
+	if opts.ExpirationWarning > 0 {
+		if err := b.warnExpirations(ctx, asm, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assemblePrefix busca os parâmetros de um prefixo e constrói sua estrutura, separando
+// os documentos JSON Patch sob PatchPath. O contexto já deve conter o cliente do prefixo
+// (withPrefixClient).
+func (b *ConfigBuilder) assemblePrefix(ctx context.Context, spec prefixOptions, opts BuildOptions, fetch fetchFunc) (map[string]interface{}, map[string]string, []types.Parameter, []patchDocument, error) {
+	params, err := fetch(ctx, spec.path)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("erro ao buscar parâmetros do prefixo %s: %w", spec.path, err)
+	}
+
+	params, err = b.applyPrefixHooks(ctx, params, spec, opts.Decryptors)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	if opts.Limits.enabled() {
+		if err := b.checkLimits(params, opts); err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+
+	configParams, patches, err := b.splitPatches(params, spec.path, opts)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	var prefixConfig map[string]interface{}
+	var prefixOwners map[string]string
+	if opts.YAMLRules {
+		// Modo YAML para regras
+		prefixConfig, prefixOwners, err = b.buildYAMLStructure(configParams, spec.path, spec.stripPrefix)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+	} else {
+		// Modo JSON padrão
+		values, err := b.decodeParameters(configParams, spec, opts)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		prefixConfig = b.buildStructure(configParams, values, spec.path, spec.stripPrefix, opts.SortByDependencies)
+		prefixOwners = b.parameterOwners(configParams, spec.path, spec.stripPrefix)
+	}
+
+	if sanitize := b.sanitizer(opts); sanitize != nil {
+		prefixConfig, err = b.rewriteKeys(prefixConfig, "", sanitize)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		prefixOwners = b.rewriteOwners(prefixOwners, sanitize)
+	}
+
+	return prefixConfig, prefixOwners, params, patches, nil
+}
+
+// render serializa a configuração montada no formato solicitado
+func (b *ConfigBuilder) render(ctx context.Context, asm *assembly, opts BuildOptions) ([]byte, error) {
+	output := b.wrapRootKey(asm.config, opts.RootKey)
+
+	if opts.YAMLRules {
+		if !opts.YAMLComments && !opts.YAMLAnchors && !opts.YAMLMultiDocument && !opts.SortKeys {
+			return yaml.Marshal(output)
+		}
+
+		var comments map[string]string
+		if opts.YAMLComments {
+			var err error
+			comments, err = b.keyDescriptions(ctx, asm)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return b.marshalYAMLNode(output, opts, comments)
+	}
+
+	if opts.NDJSONOutput {
+		return b.marshalNDJSON(output, opts)
+	}
+
+	if opts.EnvOutput != EnvNone {
+		return b.marshalEnv(ctx, output, opts)
+	}
+
+	if opts.JSONCOutput {
+		return b.marshalJSONC(ctx, asm, output, opts)
+	}
+
+	if opts.GraphQLOutput {
+		introspection, err := b.introspectionSchema(asm.config)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar a introspecção GraphQL: %w", err)
+		}
+		return marshalJSON(introspection, opts)
+	}
+
+	return marshalJSON(output, opts)
 }
 
 // BuildJsonFromPrefix método simplificado
@@ -78,14 +420,25 @@ func (b *ConfigBuilder) BuildJsonFromPrefix(ctx context.Context, prefix string,
 	return b.BuildConfigFromPrefixes(ctx, opts)
 }
 
-// BuildYamlFromPrefix método simplificado
+// BuildYamlFromPrefix método simplificado. A ordenação por dependências não se aplica
+// a regras YAML, portanto sortByDependencies é ignorado.
 func (b *ConfigBuilder) BuildYamlFromPrefix(ctx context.Context, prefix string, sortByDependencies bool) ([]byte, error) {
 	opts := BuildOptions{
 		Prefixes:           []string{prefix},
 		StripPrefix:        true,
 		JSONOutput:         false,
 		YAMLRules:          true,
-		SortByDependencies: sortByDependencies,
+		SortByDependencies: false,
 	}
 	return b.BuildConfigFromPrefixes(ctx, opts)
 }
+
+// BuildMapFromPrefix método simplificado que retorna o mapa montado
+func (b *ConfigBuilder) BuildMapFromPrefix(ctx context.Context, prefix string, sortByDependencies bool) (map[string]interface{}, error) {
+	opts := BuildOptions{
+		Prefixes:           []string{prefix},
+		StripPrefix:        true,
+		SortByDependencies: sortByDependencies,
+	}
+	return b.BuildMap(ctx, opts)
+}
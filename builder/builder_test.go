@@ -0,0 +1,255 @@
+package builder_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+// decode interpreta a saída JSON do builder
+func decode(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("saída não é JSON válido: %v\n%s", err, data)
+	}
+	return result
+}
+
+func TestBuildConfigFromPrefixes(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/prod/api/db/host":   "localhost",
+		"/app/prod/api/cache/ttl": "300",
+		"/app/prod/api/debug":     "true",
+		"/app/prod/other/key":     "ignorado",
+	})
+
+	data, err := builder.New(fake).BuildConfigFromPrefixes(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app/prod/api"},
+		StripPrefix: true,
+	})
+	if err != nil {
+		t.Fatalf("BuildConfigFromPrefixes: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"db":    map[string]interface{}{"host": "localhost"},
+		"cache": map[string]interface{}{"ttl": float64(300)},
+		"debug": true,
+	}
+	if got := decode(t, data); !reflect.DeepEqual(got, want) {
+		t.Errorf("configuração = %v, esperado %v", got, want)
+	}
+}
+
+func TestBuildConfigFromPrefixesPagination(t *testing.T) {
+	fake := ssmtest.New()
+	fake.PageSize = 2
+	fake.Seed(map[string]string{
+		"/app/prod/api/a": "1",
+		"/app/prod/api/b": "2",
+		"/app/prod/api/c": "3",
+		"/app/prod/api/d": "4",
+		"/app/prod/api/e": "5",
+	})
+
+	data, err := builder.New(fake).BuildConfigFromPrefixes(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app/prod/api"},
+		StripPrefix: true,
+	})
+	if err != nil {
+		t.Fatalf("BuildConfigFromPrefixes: %v", err)
+	}
+
+	if got := decode(t, data); len(got) != 5 {
+		t.Errorf("configuração com %d chaves, esperado 5: %v", len(got), got)
+	}
+	if calls := fake.Calls("GetParametersByPath"); calls != 3 {
+		t.Errorf("GetParametersByPath chamado %d vezes, esperado 3", calls)
+	}
+}
+
+func TestBuildConfigFromPrefixesSecureString(t *testing.T) {
+	tests := []struct {
+		name           string
+		withDecryption bool
+		want           string
+	}{
+		{name: "cifrado por padrão", withDecryption: false, want: ssmtest.Ciphertext("s3cr3t")},
+		{name: "decifrado com WithDecryption", withDecryption: true, want: "s3cr3t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := ssmtest.New()
+			fake.Put(ssmtest.Parameter{Name: "/app/prod/api/db/password", Value: "s3cr3t", Type: types.ParameterTypeSecureString})
+			fake.Put(ssmtest.Parameter{Name: "/app/prod/api/user", Value: "admin"})
+
+			data, err := builder.New(fake).BuildConfigFromPrefixes(context.Background(), builder.BuildOptions{
+				Prefixes:       []string{"/app/prod/api"},
+				StripPrefix:    true,
+				WithDecryption: tt.withDecryption,
+			})
+			if err != nil {
+				t.Fatalf("BuildConfigFromPrefixes: %v", err)
+			}
+
+			want := map[string]interface{}{
+				"db":   map[string]interface{}{"password": tt.want},
+				"user": "admin",
+			}
+			if got := decode(t, data); !reflect.DeepEqual(got, want) {
+				t.Errorf("configuração = %v, esperado %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBuildStatsMergeConflicts(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/base/api/hosts":        `["a"]`,
+		"/app/base/api/mode":         "simple",
+		"/app/base/api/http/timeout": "10",
+		"/app/prod/api/hosts":        `["b"]`,
+		"/app/prod/api/mode":         `{"name": "cluster"}`,
+		"/app/prod/api/http/timeout": "30",
+	})
+
+	var stats builder.BuildStats
+	_, err := builder.New(fake).BuildConfigFromPrefixes(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app/base", "/app/prod"},
+		StripPrefix: true,
+		OnStats:     func(s builder.BuildStats) { stats = s },
+	})
+	if err != nil {
+		t.Fatalf("BuildConfigFromPrefixes: %v", err)
+	}
+
+	// A lista concatenada por MergeAppend não é conflito
+	want := []string{"api.http.timeout", "api.mode"}
+	if !reflect.DeepEqual(stats.MergeConflicts, want) {
+		t.Errorf("MergeConflicts = %v, esperado %v", stats.MergeConflicts, want)
+	}
+}
+
+func TestBuildConfigFromPrefixesEnvDuplicate(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/prod/api/db-host":    "a",
+		"/app/prod/api/db_host":    "b",
+		"/app/prod/api/db.host":    "c",
+		"/app/prod/api/cache/size": "10",
+	})
+	opts := builder.BuildOptions{
+		Prefixes:    []string{"/app/prod/api"},
+		StripPrefix: true,
+		EnvOutput:   builder.EnvLambda,
+	}
+
+	_, first := builder.New(fake).BuildConfigFromPrefixes(context.Background(), opts)
+	if first == nil {
+		t.Fatal("esperado erro de variável duplicada")
+	}
+	for i := 0; i < 20; i++ {
+		_, err := builder.New(fake).BuildConfigFromPrefixes(context.Background(), opts)
+		if err == nil || err.Error() != first.Error() {
+			t.Fatalf("mensagem não determinística: %v, antes %v", err, first)
+		}
+	}
+}
+
+// TestConcurrentBuildAndSetClient exercita construções simultâneas à troca dos clientes e
+// dos limites do builder; execute com -race.
+func TestConcurrentBuildAndSetClient(t *testing.T) {
+	seed := map[string]string{
+		"/app/base/api/db/host":   "localhost",
+		"/app/base/api/debug":     "false",
+		"/app/prod/api/cache/ttl": "300",
+		"/app/prod/api/debug":     "true",
+	}
+	fakes := []*ssmtest.Client{ssmtest.New(), ssmtest.New()}
+	for _, fake := range fakes {
+		fake.Seed(seed)
+	}
+
+	b := builder.New(fakes[0])
+	ctx := context.Background()
+	opts := builder.BuildOptions{
+		Prefixes:    []string{"/app/base", "/app/prod"},
+		StripPrefix: true,
+	}
+
+	const iterations = 50
+	builds := []func() error{
+		func() error {
+			_, err := b.BuildConfigFromPrefixes(ctx, opts)
+			return err
+		},
+		func() error {
+			_, err := b.BuildMap(ctx, opts)
+			return err
+		},
+		func() error {
+			_, _, err := b.BuildIfModified(ctx, opts, "")
+			return err
+		},
+		func() error {
+			_, err := b.BuildMany(ctx, []builder.OutputSpec{
+				{Name: "base", Options: builder.BuildOptions{Prefixes: []string{"/app/base"}, StripPrefix: true}},
+				{Name: "prod", Options: builder.BuildOptions{Prefixes: []string{"/app/prod"}, StripPrefix: true}},
+			})
+			return err
+		},
+		func() error {
+			_, err := b.Chain().Then("/app/base").Then("/app/prod").Build(ctx)
+			return err
+		},
+	}
+	setters := []func(i int){
+		func(i int) { b.SetSSMClient(fakes[i%len(fakes)]) },
+		func(i int) { b.SetS3Client(s3.New(s3.Options{Region: "us-east-1"})) },
+		func(i int) { b.SetCache(builder.NewMemoryCache(), time.Minute) },
+		func(i int) { b.SetRateLimit(float64(1000*(i%2)), 10) },
+		func(i int) { b.SetMaxConcurrency(i % 3) },
+		func(i int) { b.SetClientLocation("us-east-1", "123456789012") },
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(builds)*iterations)
+	for _, build := range builds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				if err := build(); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	for _, set := range setters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				set(i)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("construção concorrente: %v", err)
+	}
+}
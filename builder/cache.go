@@ -0,0 +1,172 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// Cache armazena os parâmetros já buscados de cada prefixo. Uma mesma instância pode ser
+// compartilhada por vários ConfigBuilder (ex.: memória, Redis ou disco) para evitar que
+// componentes do mesmo processo busquem repetidamente os mesmos prefixos.
+// As implementações devem ser seguras para uso concorrente.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// SharedCache cache em memória de processo, pronto para ser compartilhado entre builders
+var SharedCache = NewMemoryCache()
+
+// MemoryCache implementação de Cache em memória com expiração por TTL
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+}
+
+// memoryEntry entrada do MemoryCache
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache cria um cache em memória vazio
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Get retorna o valor armazenado, se existir e não estiver expirado
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set armazena o valor; ttl zero mantém a entrada até ser removida
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
+
+// Delete remove a entrada
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+// SetCache define o cache de parâmetros do builder e o TTL das entradas gravadas.
+// Informe nil para desabilitar o cache.
+func (b *ConfigBuilder) SetCache(cache Cache, ttl time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cache = cache
+	b.cacheTTL = ttl
+}
+
+// cacheKey identifica no cache os parâmetros de um prefixo buscados com as opções informadas
+func (b *ConfigBuilder) cacheKey(prefix string, opts BuildOptions) string {
+	recursive := opts.Recursive == nil || *opts.Recursive
+	return fmt.Sprintf("ssm:%s|recursive=%t|max=%d|truncate=%t|decrypt=%t", prefix, recursive, opts.MaxParameters, opts.TruncateParameters, opts.WithDecryption)
+}
+
+// scopedCacheKey inclui na chave do cache a região e a conta do cliente associado ao contexto
+func (b *ConfigBuilder) scopedCacheKey(ctx context.Context, prefix string, opts BuildOptions) string {
+	key := b.cacheKey(prefix, opts)
+	if scope := b.clientScope(ctx); scope != "" {
+		key = scope + "|" + key
+	}
+	return key
+}
+
+// Invalidate remove do cache os parâmetros dos prefixos das opções, para que a próxima
+// construção com as mesmas opções os busque novamente. Sem cache configurado, não faz nada.
+func (b *ConfigBuilder) Invalidate(ctx context.Context, opts BuildOptions) error {
+	b.mu.RLock()
+	cache := b.cache
+	b.mu.RUnlock()
+
+	if cache == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, spec := range b.resolvePrefixes(opts) {
+		prefixCtx, err := b.withPrefixClient(ctx, spec, opts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		key := b.scopedCacheKey(prefixCtx, spec.path, opts)
+		if err := cache.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("erro ao remover %s do cache: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cachedParameters busca os parâmetros do prefixo passando pelo cache, quando configurado.
+// Falhas do cache são reportadas como aviso e não interrompem a construção.
+func (b *ConfigBuilder) cachedParameters(ctx context.Context, prefix string, opts BuildOptions) ([]types.Parameter, error) {
+	b.mu.RLock()
+	cache, ttl := b.cache, b.cacheTTL
+	b.mu.RUnlock()
+
+	if cache == nil {
+		return b.getParametersByPath(ctx, prefix, opts)
+	}
+
+	key := b.scopedCacheKey(ctx, prefix, opts)
+	data, ok, err := cache.Get(ctx, key)
+	if err != nil {
+		b.warn(ctx, opts, "erro ao ler o cache %s: %v", key, err)
+	}
+	if ok {
+		var params []types.Parameter
+		if err := json.Unmarshal(data, &params); err == nil {
+			return params, nil
+		}
+		b.warn(ctx, opts, "entrada de cache inválida %s, buscando novamente", key)
+	}
+
+	params, err := b.getParametersByPath(ctx, prefix, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(params)
+	if err == nil {
+		err = cache.Set(ctx, key, data, ttl)
+	}
+	if err != nil {
+		b.warn(ctx, opts, "erro ao gravar o cache %s: %v", key, err)
+	}
+	return params, nil
+}
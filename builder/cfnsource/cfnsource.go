@@ -0,0 +1,157 @@
+// Package cfnsource fornece fontes (builder.Source) com os exports e os outputs de stacks
+// do CloudFormation, para que valores de infraestrutura (IDs de VPC, ARNs, endpoints)
+// entrem na configuração sem serem duplicados manualmente no Parameter Store.
+//
+//	cfn := cloudformation.NewFromConfig(cfg)
+//	data, err := svc.Chain().
+//		Defaults(cfnsource.Exports(cfn, cfnsource.Options{Key: "infra", NamePrefix: "network-"})).
+//		Then("/app/prod").
+//		Build(ctx)
+package cfnsource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/raywall/go-libs-config/builder"
+)
+
+// API operações do CloudFormation utilizadas pelas fontes. *cloudformation.Client
+// satisfaz a interface.
+type API interface {
+	ListExports(ctx context.Context, params *cloudformation.ListExportsInput, optFns ...func(*cloudformation.Options)) (*cloudformation.ListExportsOutput, error)
+	DescribeStacks(ctx context.Context, params *cloudformation.DescribeStacksInput, optFns ...func(*cloudformation.Options)) (*cloudformation.DescribeStacksOutput, error)
+}
+
+// Options opções das fontes do CloudFormation
+type Options struct {
+	Key        string // Chave sob a qual os valores são montados (vazio = raiz)
+	NamePrefix string // Considera apenas os exports (ou outputs) cujo nome começa com esse prefixo
+	TrimPrefix bool   // Remove NamePrefix do nome das chaves geradas
+}
+
+// source fonte do CloudFormation que também implementa builder.Pinger
+type source struct {
+	load func(ctx context.Context) (map[string]interface{}, error)
+	ping func(ctx context.Context) error
+}
+
+// Load implementa builder.Source
+func (s source) Load(ctx context.Context) (map[string]interface{}, error) {
+	return s.load(ctx)
+}
+
+// Ping implementa builder.Pinger
+func (s source) Ping(ctx context.Context) error {
+	return s.ping(ctx)
+}
+
+// Exports retorna uma fonte com os exports da região, no formato nome do export -> valor.
+// A fonte implementa builder.Pinger, verificado com a primeira página de ListExports.
+func Exports(client API, opts Options) builder.Source {
+	load := func(ctx context.Context) (map[string]interface{}, error) {
+		values := make(map[string]interface{})
+
+		var token *string
+		for {
+			result, err := client.ListExports(ctx, &cloudformation.ListExportsInput{NextToken: token})
+			if err != nil {
+				return nil, fmt.Errorf("erro ao listar os exports do CloudFormation: %w", err)
+			}
+
+			for _, export := range result.Exports {
+				if key, ok := opts.key(aws.ToString(export.Name)); ok {
+					values[key] = aws.ToString(export.Value)
+				}
+			}
+
+			if result.NextToken == nil {
+				break
+			}
+			token = result.NextToken
+		}
+
+		return opts.mount(values), nil
+	}
+
+	ping := func(ctx context.Context) error {
+		if _, err := client.ListExports(ctx, &cloudformation.ListExportsInput{}); err != nil {
+			return fmt.Errorf("erro ao listar os exports do CloudFormation: %w", err)
+		}
+		return nil
+	}
+	return source{load: load, ping: ping}
+}
+
+// Outputs retorna uma fonte com os outputs das stacks informadas, no formato
+// nome da stack -> chave do output -> valor. Com uma única stack, os outputs ficam
+// diretamente sob Key (ou na raiz). A fonte implementa builder.Pinger, verificando a
+// existência de cada stack.
+func Outputs(client API, opts Options, stacks ...string) builder.Source {
+	describe := func(ctx context.Context, stack string) (*cloudformation.DescribeStacksOutput, error) {
+		result, err := client.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String(stack)})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar os outputs da stack %s: %w", stack, err)
+		}
+		if len(result.Stacks) == 0 {
+			return nil, fmt.Errorf("stack %s não encontrada", stack)
+		}
+		return result, nil
+	}
+
+	load := func(ctx context.Context) (map[string]interface{}, error) {
+		values := make(map[string]interface{}, len(stacks))
+
+		for _, stack := range stacks {
+			result, err := describe(ctx, stack)
+			if err != nil {
+				return nil, err
+			}
+
+			outputs := make(map[string]interface{})
+			for _, output := range result.Stacks[0].Outputs {
+				if key, ok := opts.key(aws.ToString(output.OutputKey)); ok {
+					outputs[key] = aws.ToString(output.OutputValue)
+				}
+			}
+			values[stack] = outputs
+		}
+
+		if len(stacks) == 1 {
+			return opts.mount(values[stacks[0]].(map[string]interface{})), nil
+		}
+		return opts.mount(values), nil
+	}
+
+	ping := func(ctx context.Context) error {
+		for _, stack := range stacks {
+			if _, err := describe(ctx, stack); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return source{load: load, ping: ping}
+}
+
+// key aplica NamePrefix e TrimPrefix ao nome, indicando se ele deve ser incluído
+func (o Options) key(name string) (string, bool) {
+	if !strings.HasPrefix(name, o.NamePrefix) {
+		return "", false
+	}
+	if o.TrimPrefix {
+		name = strings.TrimPrefix(name, o.NamePrefix)
+	}
+	return name, name != ""
+}
+
+// mount monta os valores sob Key, quando informada
+func (o Options) mount(values map[string]interface{}) map[string]interface{} {
+	if o.Key == "" {
+		return values
+	}
+	return map[string]interface{}{o.Key: values}
+}
@@ -0,0 +1,189 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// Chain monta a configuração em camadas com precedência explícita: cada camada sobrepõe
+// as anteriores. Mapas são mesclados recursivamente; listas e valores escalares da camada
+// mais recente substituem os anteriores. Com MergeStrategy igual a MergePatch, as camadas
+// seguem JSON Merge Patch (RFC 7386) e valores nulos removem as chaves das anteriores.
+//
+//	svc.Chain().Defaults(builder.NewFileSource("defaults.yaml")).Then("/app/base").Then("/app/prod").Build(ctx)
+type Chain struct {
+	builder  *ConfigBuilder
+	opts     BuildOptions
+	layers   []chainLayer
+	defaults int // Quantidade de camadas de Defaults, sempre no início de layers
+}
+
+// chainLayer camada da Chain: um prefixo do Parameter Store ou uma Source
+type chainLayer struct {
+	prefix string
+	source Source
+}
+
+// Chain inicia uma construção em camadas. Por padrão os prefixos são removidos dos nomes
+// (StripPrefix) e a saída é JSON compacto; use WithOptions para alterar.
+func (b *ConfigBuilder) Chain() *Chain {
+	return &Chain{
+		builder: b,
+		opts:    BuildOptions{StripPrefix: true},
+	}
+}
+
+// WithOptions define as opções usadas na busca de cada prefixo e na serialização final.
+// Prefixes e PrefixSpecs são ignorados: os prefixos vêm das camadas.
+func (c *Chain) WithOptions(opts BuildOptions) *Chain {
+	c.opts = opts
+	return c
+}
+
+// Defaults adiciona fontes de valores padrão. Elas têm precedência menor que todas as
+// camadas adicionadas por Then e ThenSource, independentemente da ordem das chamadas; entre
+// si, as fontes de Defaults seguem a ordem em que foram adicionadas.
+func (c *Chain) Defaults(sources ...Source) *Chain {
+	layers := make([]chainLayer, 0, len(c.layers)+len(sources))
+	layers = append(layers, c.layers[:c.defaults]...)
+	for _, source := range sources {
+		layers = append(layers, chainLayer{source: source})
+	}
+	c.layers = append(layers, c.layers[c.defaults:]...)
+	c.defaults += len(sources)
+	return c
+}
+
+// Then adiciona um prefixo do Parameter Store que sobrepõe as camadas anteriores
+func (c *Chain) Then(prefix string) *Chain {
+	c.layers = append(c.layers, chainLayer{prefix: prefix})
+	return c
+}
+
+// ThenSource adiciona uma fonte que sobrepõe as camadas anteriores
+func (c *Chain) ThenSource(source Source) *Chain {
+	c.layers = append(c.layers, chainLayer{source: source})
+	return c
+}
+
+// Build monta as camadas e serializa o resultado conforme as opções
+func (c *Chain) Build(ctx context.Context) ([]byte, error) {
+	ctx, stats := c.builder.withStats(ctx, c.opts)
+	asm, err := c.assemble(ctx)
+	if err != nil {
+		return nil, c.builder.annotate(ctx, err)
+	}
+
+	data, err := c.builder.render(ctx, asm, c.opts)
+	if err == nil && c.opts.Gzip {
+		data, err = c.builder.compress(data)
+	}
+	if err == nil && stats != nil {
+		c.opts.OnStats(stats.report(asm, data))
+	}
+	data, err = c.builder.partialResult(data, err, asm.skipped)
+	return data, c.builder.annotate(ctx, err)
+}
+
+// Load monta as camadas e retorna o acesso tipado ao resultado
+func (c *Chain) Load(ctx context.Context) (*Config, error) {
+	ctx, stats := c.builder.withStats(ctx, c.opts)
+	asm, err := c.assemble(ctx)
+	if err != nil {
+		return nil, c.builder.annotate(ctx, err)
+	}
+	if stats != nil {
+		c.opts.OnStats(stats.report(asm, nil))
+	}
+
+	config := NewConfig(c.builder.wrapRootKey(asm.config, c.opts.RootKey))
+	if len(asm.skipped) > 0 {
+		return config, c.builder.annotate(ctx, &PartialError{Skipped: asm.skipped})
+	}
+	return config, nil
+}
+
+// assemble busca cada camada em ordem e a sobrepõe às anteriores. As etapas posteriores à
+// mescla (remapeamentos, patches, condições, tipos, validações, ordenação e metadados) são
+// executadas uma única vez, sobre o resultado de todas as camadas.
+func (c *Chain) assemble(ctx context.Context) (*assembly, error) {
+	result := newAssembly()
+	var patches []patchDocument
+
+	for i, layer := range c.layers {
+		if layer.source != nil {
+			layerConfig, err := layer.source.Load(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao carregar a camada %d: %w", i, err)
+			}
+			// As chaves das fontes passam pela mesma sanitização e canonicalização dos prefixos
+			if sanitize := c.builder.sanitizer(c.opts); sanitize != nil {
+				if layerConfig, err = c.builder.rewriteKeys(layerConfig, "", sanitize); err != nil {
+					return nil, fmt.Errorf("erro nas chaves da camada %d: %w", i, err)
+				}
+			}
+			if c.opts.CaseInsensitiveKeys {
+				canonical := c.builder.canonicalizer(c.opts.KeyCase, result.keyForms)
+				if layerConfig, err = c.builder.rewriteKeys(layerConfig, "", canonical); err != nil {
+					return nil, fmt.Errorf("erro nas chaves da camada %d: %w", i, err)
+				}
+			}
+			c.mergeLayer(ctx, result.config, layerConfig)
+			continue
+		}
+
+		opts := c.opts
+		opts.Prefixes = []string{layer.prefix}
+		opts.PrefixSpecs = nil
+		opts.MountKeys = nil
+		if err := opts.Validate(); err != nil {
+			return nil, err
+		}
+
+		// A grafia canônica das chaves é compartilhada entre as camadas
+		asm := newAssembly()
+		asm.keyForms = result.keyForms
+		layerPatches, err := c.builder.assemblePrefixes(ctx, asm, opts, func(ctx context.Context, prefix string) ([]types.Parameter, error) {
+			return c.builder.fetchPrefix(ctx, prefix, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		c.mergeLayer(ctx, result.config, asm.config)
+		for key, name := range asm.owners {
+			result.owners[key] = name
+		}
+		patches = append(patches, layerPatches...)
+		result.params = append(result.params, asm.params...)
+		result.fetched = append(result.fetched, asm.fetched...)
+		result.prefixContexts = append(result.prefixContexts, asm.prefixContexts...)
+		for name, prefixCtx := range asm.paramContexts {
+			result.paramContexts[name] = prefixCtx
+		}
+		result.skipped = append(result.skipped, asm.skipped...)
+	}
+
+	if err := c.builder.finishAssembly(ctx, result, patches, c.opts); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeLayer sobrepõe a camada ao resultado acumulado
+func (c *Chain) mergeLayer(ctx context.Context, dest, src map[string]interface{}) {
+	strategy := MergeOverride
+	if c.opts.MergeStrategy == MergePatch {
+		strategy = MergePatch
+	}
+	if stats := statsFrom(ctx); stats != nil {
+		stats.addConflicts(dest, src, "", strategy)
+	}
+	if strategy == MergePatch {
+		c.builder.mergePatch(dest, src)
+		return
+	}
+	c.builder.overrideMaps(dest, src)
+}
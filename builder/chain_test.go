@@ -0,0 +1,75 @@
+package builder_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+func TestChainSourceKeys(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/prod/db_host":   "prod-db",
+		"/app/prod/cache/ttl": "300",
+	})
+
+	tests := []struct {
+		name     string
+		opts     builder.BuildOptions
+		defaults builder.MapSource
+		want     map[string]interface{}
+	}{
+		{
+			name:     "sanitização",
+			opts:     builder.BuildOptions{StripPrefix: true, KeySanitization: builder.KeySanitizeReplace},
+			defaults: builder.MapSource{"db host": "localhost", "retries": float64(3)},
+			want: map[string]interface{}{
+				"db_host": "prod-db",
+				"cache":   map[string]interface{}{"ttl": float64(300)},
+				"retries": float64(3),
+			},
+		},
+		{
+			name:     "chaves sem distinção de maiúsculas",
+			opts:     builder.BuildOptions{StripPrefix: true, CaseInsensitiveKeys: true},
+			defaults: builder.MapSource{"DB_HOST": "localhost", "Cache": map[string]interface{}{"TTL": float64(60), "Size": float64(10)}},
+			want: map[string]interface{}{
+				"db_host": "prod-db",
+				"cache":   map[string]interface{}{"ttl": float64(300), "size": float64(10)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := builder.New(fake).Chain().WithOptions(tt.opts).
+				Defaults(tt.defaults).
+				Then("/app/prod").
+				Load(context.Background())
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if got := config.Map(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("configuração = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainSourceInvalidKey(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/prod/db/host": "prod-db"})
+
+	_, err := builder.New(fake).Chain().
+		WithOptions(builder.BuildOptions{StripPrefix: true, KeySanitization: builder.KeySanitizeError}).
+		Defaults(builder.MapSource{"db.host": "localhost"}).
+		Then("/app/prod").
+		Build(context.Background())
+	if !errors.Is(err, builder.ErrInvalidKey) {
+		t.Errorf("erro %v, esperado ErrInvalidKey", err)
+	}
+}
@@ -0,0 +1,149 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// ErrForeignARN indica um prefixo informado como ARN de uma região ou conta diferente da do
+// cliente padrão (ou não confirmada), sem ARNClient que forneça o cliente correspondente
+var ErrForeignARN = errors.New("ARN de outra região ou conta sem ARNClient")
+
+// SetClientLocation informa a região e a conta do cliente padrão, usadas para aceitar
+// prefixos informados como ARN sem ARNClient. A região de um *ssm.Client é obtida das
+// opções do cliente quando region é vazia; a conta não pode ser inferida sem chamadas ao STS.
+func (b *ConfigBuilder) SetClientLocation(region, account string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.region = region
+	b.account = account
+}
+
+// checkDefaultLocation verifica se o ARN pertence à região e à conta do cliente padrão
+func (b *ConfigBuilder) checkDefaultLocation(arn parameterARN) error {
+	b.mu.RLock()
+	region, account, client := b.region, b.account, b.ssmClient
+	b.mu.RUnlock()
+
+	if region == "" {
+		if configured, ok := client.(interface{ Options() ssm.Options }); ok {
+			region = configured.Options().Region
+		}
+	}
+	if region == "" || account == "" {
+		return fmt.Errorf("%w: não foi possível confirmar a região e a conta do cliente padrão para %s (use SetClientLocation ou ARNClient)", ErrForeignARN, arn.Path)
+	}
+	if arn.Region != region || arn.Account != account {
+		return fmt.Errorf("%w: %s pertence a %s:%s, e o cliente padrão a %s:%s", ErrForeignARN, arn.Path, arn.Region, arn.Account, region, account)
+	}
+	return nil
+}
+
+// scopedClient cliente SSM de um prefixo e o escopo (perfil, conta ou instância do cliente)
+// que distingue seus parâmetros no cache
+type scopedClient struct {
+	client SSMAPI
+	scope  string
+}
+
+// withPrefixClient associa ao contexto da busca o cliente do prefixo: o Client do
+// PrefixSpec, o cliente do AWSProfile ou, para prefixos informados por ARN, o retornado por
+// ARNClient, nessa ordem. Sem nenhum deles, o contexto é mantido e o cliente padrão é
+// utilizado; prefixos informados por ARN exigem então a região e a conta do cliente padrão.
+func (b *ConfigBuilder) withPrefixClient(ctx context.Context, spec prefixOptions, opts BuildOptions) (context.Context, error) {
+	switch {
+	case spec.client != nil:
+		return context.WithValue(ctx, clientKey, scopedClient{
+			client: spec.client,
+			scope:  clientIdentity(spec.client),
+		}), nil
+	case spec.awsProfile != "":
+		client, err := b.profileClient(ctx, spec.awsProfile)
+		if err != nil {
+			return nil, err
+		}
+		return context.WithValue(ctx, clientKey, scopedClient{
+			client: client,
+			scope:  "profile:" + spec.awsProfile,
+		}), nil
+	case spec.arn.Path != "":
+		if opts.ARNClient != nil {
+			client, err := opts.ARNClient(spec.arn.Region, spec.arn.Account)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao obter o cliente da conta %s na região %s: %w", spec.arn.Account, spec.arn.Region, err)
+			}
+			if client != nil {
+				return context.WithValue(ctx, clientKey, scopedClient{
+					client: client,
+					scope:  spec.arn.Region + ":" + spec.arn.Account,
+				}), nil
+			}
+		}
+		if err := b.checkDefaultLocation(spec.arn); err != nil {
+			return nil, err
+		}
+		return ctx, nil
+	default:
+		return ctx, nil
+	}
+}
+
+// profileClient retorna o cliente SSM do perfil de credenciais da AWS, criado a partir da
+// configuração compartilhada (~/.aws/config) na primeira utilização e reaproveitado depois
+func (b *ConfigBuilder) profileClient(ctx context.Context, profile string) (SSMAPI, error) {
+	b.mu.RLock()
+	client, ok := b.profileClients[profile]
+	b.mu.RUnlock()
+	if ok {
+		return client, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithSharedConfigProfile(profile))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar o perfil AWS %s: %w", profile, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if existing, ok := b.profileClients[profile]; ok {
+		return existing, nil
+	}
+	if b.profileClients == nil {
+		b.profileClients = make(map[string]SSMAPI)
+	}
+	b.profileClients[profile] = ssm.NewFromConfig(cfg)
+	return b.profileClients[profile], nil
+}
+
+// clientFrom retorna o cliente associado ao contexto ou o cliente padrão do builder, com
+// os limites de taxa e de concorrência aplicados
+func (b *ConfigBuilder) clientFrom(ctx context.Context) SSMAPI {
+	if scoped, ok := ctx.Value(clientKey).(scopedClient); ok {
+		return b.limited(scoped.client)
+	}
+	return b.limited(b.client())
+}
+
+// clientScope retorna o escopo do cliente associado ao contexto, se houver
+func (b *ConfigBuilder) clientScope(ctx context.Context) string {
+	if scoped, ok := ctx.Value(clientKey).(scopedClient); ok {
+		return scoped.scope
+	}
+	return ""
+}
+
+// clientIdentity identifica a instância do cliente no cache. Como depende do endereço em
+// memória, entradas de clientes informados diretamente não são compartilhadas entre
+// processos; use AWSProfile para um escopo estável.
+func clientIdentity(client SSMAPI) string {
+	value := reflect.ValueOf(client)
+	if value.Kind() == reflect.Pointer {
+		return fmt.Sprintf("client:%T@%x", client, value.Pointer())
+	}
+	return fmt.Sprintf("client:%T", client)
+}
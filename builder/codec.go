@@ -0,0 +1,151 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Codec decodifica o valor textual de um parâmetro no valor da árvore de configuração
+type Codec interface {
+	Decode(value string) (interface{}, error)
+}
+
+// CodecFunc adapta uma função à interface Codec
+type CodecFunc func(value string) (interface{}, error)
+
+// Decode implementa Codec
+func (f CodecFunc) Decode(value string) (interface{}, error) {
+	return f(value)
+}
+
+var (
+	// JSONCodec interpreta o valor como JSON, retornando erro quando ele não é válido
+	JSONCodec Codec = CodecFunc(func(value string) (interface{}, error) {
+		var result interface{}
+		if err := json.Unmarshal([]byte(value), &result); err != nil {
+			return nil, fmt.Errorf("JSON inválido: %w", err)
+		}
+		return result, nil
+	})
+
+	// YAMLCodec interpreta o valor como YAML
+	YAMLCodec Codec = yamlCodec{}
+
+	// RawCodec mantém o valor como texto (ex.: certificados PEM)
+	RawCodec Codec = CodecFunc(func(value string) (interface{}, error) {
+		return value, nil
+	})
+)
+
+// yamlCodec implementação de YAMLCodec, identificável pelo tipo para que os limites de
+// aninhamento usem a mesma sintaxe da decodificação
+type yamlCodec struct{}
+
+// Decode implementa Codec
+func (yamlCodec) Decode(value string) (interface{}, error) {
+	var result interface{}
+	if err := yaml.Unmarshal([]byte(value), &result); err != nil {
+		return nil, fmt.Errorf("YAML inválido: %w", err)
+	}
+	return result, nil
+}
+
+// CodecRegistry seleciona o Codec de cada parâmetro pelo padrão do nome (sintaxe de
+// path.Match) ou pelo sufixo. Padrões têm precedência sobre sufixos; entre eles, os mais
+// longos (mais específicos) são avaliados primeiro. É seguro para uso concorrente.
+type CodecRegistry struct {
+	mu       sync.RWMutex
+	suffixes map[string]Codec
+	patterns map[string]Codec
+
+	// fallback codec dos parâmetros sem correspondência (nil = JSON com fallback para texto)
+	fallback Codec
+}
+
+// NewCodecRegistry cria um registro vazio
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{
+		suffixes: make(map[string]Codec),
+		patterns: make(map[string]Codec),
+	}
+}
+
+// DefaultCodecs cria um registro com os sufixos ".json" (JSONCodec), ".yaml" e ".yml"
+// (YAMLCodec), ".pem", ".crt", ".key" e ".txt" (RawCodec)
+func DefaultCodecs() *CodecRegistry {
+	r := NewCodecRegistry()
+	r.RegisterSuffix(".json", JSONCodec)
+	r.RegisterSuffix(".yaml", YAMLCodec)
+	r.RegisterSuffix(".yml", YAMLCodec)
+	for _, suffix := range []string{".pem", ".crt", ".key", ".txt"} {
+		r.RegisterSuffix(suffix, RawCodec)
+	}
+	return r
+}
+
+// RegisterSuffix associa o codec aos parâmetros cujo nome termina com o sufixo
+func (r *CodecRegistry) RegisterSuffix(suffix string, codec Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.suffixes[suffix] = codec
+}
+
+// RegisterPattern associa o codec aos parâmetros cujo nome corresponde ao padrão
+// (ex.: "/app/certs/*")
+func (r *CodecRegistry) RegisterPattern(pattern string, codec Codec) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("padrão inválido %q: %w", pattern, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns[pattern] = codec
+	return nil
+}
+
+// SetFallback define o codec dos parâmetros sem correspondência. Sem ele, os valores
+// continuam sendo interpretados como JSON e mantidos como texto quando não são JSON válido.
+func (r *CodecRegistry) SetFallback(codec Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = codec
+}
+
+// Lookup retorna o codec do parâmetro, se houver correspondência ou fallback
+func (r *CodecRegistry) Lookup(name string) (Codec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, pattern := range longestFirst(r.patterns) {
+		if matched, _ := path.Match(pattern, name); matched {
+			return r.patterns[pattern], true
+		}
+	}
+	for _, suffix := range longestFirst(r.suffixes) {
+		if strings.HasSuffix(name, suffix) {
+			return r.suffixes[suffix], true
+		}
+	}
+	return r.fallback, r.fallback != nil
+}
+
+// longestFirst retorna as chaves ordenadas da mais longa para a mais curta
+func longestFirst(codecs map[string]Codec) []string {
+	keys := make([]string, 0, len(codecs))
+	for key := range codecs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
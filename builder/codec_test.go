@@ -0,0 +1,122 @@
+package builder_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+func TestCodecRegistryLookup(t *testing.T) {
+	upper := builder.CodecFunc(func(value string) (interface{}, error) { return strings.ToUpper(value), nil })
+
+	registry := builder.DefaultCodecs()
+	registry.RegisterSuffix(".tar.json", upper)
+	if err := registry.RegisterPattern("/app/certs/*", upper); err != nil {
+		t.Fatalf("RegisterPattern: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  interface{}
+		found bool
+	}{
+		{name: "/app/settings.json", value: `{"a": 1}`, want: map[string]interface{}{"a": float64(1)}, found: true},
+		{name: "/app/settings.yaml", value: "a: 1", want: map[string]interface{}{"a": 1}, found: true},
+		{name: "/app/settings.yml", value: "- a", want: []interface{}{"a"}, found: true},
+		{name: "/app/tls/cert.pem", value: "[1]", want: "[1]", found: true},
+		{name: "/app/bundle.tar.json", value: "abc", want: "ABC", found: true},
+		{name: "/app/certs/ca.pem", value: "abc", want: "ABC", found: true},
+		{name: "/app/plain", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, found := registry.Lookup(tt.name)
+			if found != tt.found {
+				t.Fatalf("Lookup encontrou = %t, esperado %t", found, tt.found)
+			}
+			if !found {
+				return
+			}
+			got, err := codec.Decode(tt.value)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode = %#v, esperado %#v", got, tt.want)
+			}
+		})
+	}
+
+	registry.SetFallback(builder.RawCodec)
+	if codec, found := registry.Lookup("/app/plain"); !found || codec == nil {
+		t.Error("Lookup sem correspondência deveria retornar o fallback")
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	if _, err := builder.JSONCodec.Decode("{"); err == nil {
+		t.Error("JSONCodec aceitou JSON inválido")
+	}
+	if _, err := builder.YAMLCodec.Decode("a: [1"); err == nil {
+		t.Error("YAMLCodec aceitou YAML inválido")
+	}
+	if err := builder.NewCodecRegistry().RegisterPattern("/app/[", builder.RawCodec); err == nil {
+		t.Error("RegisterPattern aceitou padrão inválido")
+	}
+}
+
+func TestBuildWithCodecs(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/tls/cert.pem":  "[not json]",
+		"/app/settings.yaml": "retries: 3\nhosts: [a, b]\n",
+		"/app/limits/max":    "10",
+		"/app/owner/name":    "abc",
+	})
+
+	got, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app"},
+		StripPrefix: true,
+		Codecs:      builder.DefaultCodecs(),
+	})
+	if err != nil {
+		t.Fatalf("BuildMap: %v", err)
+	}
+
+	config := builder.NewConfig(got)
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{path: `tls.cert\.pem`, want: "[not json]"},
+		{path: `settings\.yaml.retries`, want: 3},
+		{path: `settings\.yaml.hosts`, want: []interface{}{"a", "b"}},
+		{path: "limits.max", want: float64(10)},
+		{path: "owner.name", want: "abc"},
+	}
+	for _, tt := range tests {
+		if value, _ := config.Get(tt.path); !reflect.DeepEqual(value, tt.want) {
+			t.Errorf("%s = %#v, esperado %#v", tt.path, value, tt.want)
+		}
+	}
+}
+
+func TestBuildWithCodecsInvalidValue(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/settings.json": "{", "/app/debug/on": "true"})
+
+	_, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app"},
+		StripPrefix: true,
+		Codecs:      builder.DefaultCodecs(),
+	})
+	if err == nil || !strings.Contains(err.Error(), "/app/settings.json") {
+		t.Errorf("erro %v, esperado JSON inválido em /app/settings.json", err)
+	}
+}
@@ -0,0 +1,346 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueType tipo para o qual Config.Coerce e TypeSchema convertem os valores
+type ValueType int
+
+const (
+	// ValueAuto reconhece o tipo pela forma do valor: durações com unidade ("30s"),
+	// datas RFC 3339 ("2023-01-01T00:00:00Z") e tamanhos com unidade ("512MiB")
+	ValueAuto ValueType = iota
+	// ValueDuration converte para time.Duration (formato de time.ParseDuration)
+	ValueDuration
+	// ValueTime converte para time.Time (RFC 3339)
+	ValueTime
+	// ValueSize converte para int64 com o número de bytes (formato de ParseSize)
+	ValueSize
+	// ValueString converte números e booleanos para texto
+	ValueString
+	// ValueInt converte para int64; aceita números inteiros e texto numérico
+	ValueInt
+	// ValueFloat converte para float64; aceita números e texto numérico
+	ValueFloat
+	// ValueBool converte para bool; aceita booleanos e texto como "true" e "false"
+	ValueBool
+)
+
+// String retorna o nome do tipo
+func (t ValueType) String() string {
+	switch t {
+	case ValueAuto:
+		return "auto"
+	case ValueDuration:
+		return "duration"
+	case ValueTime:
+		return "time"
+	case ValueSize:
+		return "size"
+	case ValueString:
+		return "string"
+	case ValueInt:
+		return "int"
+	case ValueFloat:
+		return "float"
+	case ValueBool:
+		return "bool"
+	default:
+		return fmt.Sprintf("ValueType(%d)", int(t))
+	}
+}
+
+// ErrCoercionFailed indica um valor que não pôde ser convertido para o tipo indicado
+var ErrCoercionFailed = errors.New("falha na conversão do valor")
+
+// durationPattern reconhece durações com unidade explícita, sem aceitar números puros
+var durationPattern = regexp.MustCompile(`^-?(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$`)
+
+// sizePattern reconhece tamanhos no formato de ParseSize
+var sizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMGTP]I?B|B)$`)
+
+// sizeUnits multiplicador de cada unidade de tamanho (decimal e binária)
+var sizeUnits = map[string]float64{
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"PB":  1e15,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+	"PIB": 1 << 50,
+}
+
+// ParseSize interpreta um tamanho com unidade decimal (B, KB, MB, GB, TB, PB) ou binária
+// (KiB, MiB, GiB, TiB, PiB), sem diferenciar maiúsculas/minúsculas, e retorna o número de
+// bytes (ex.: "512MiB" -> 536870912, "1.5GB" -> 1500000000). Inteiros sem unidade são bytes.
+func ParseSize(value string) (int64, error) {
+	if bytes, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil && bytes >= 0 {
+		return bytes, nil
+	}
+
+	match := sizePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if match == nil {
+		return 0, fmt.Errorf("tamanho inválido: %q", value)
+	}
+
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("tamanho inválido: %q", value)
+	}
+	bytes := number * sizeUnits[match[2]]
+	if bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("tamanho fora do intervalo: %q", value)
+	}
+	return int64(bytes), nil
+}
+
+// GetTime retorna o valor como time.Time; strings usam o formato RFC 3339
+func (c *Config) GetTime(path string) (time.Time, bool) {
+	value, ok := c.Get(path)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(v))
+		if err != nil {
+			return time.Time{}, false
+		}
+		return parsed, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// GetSize retorna o valor como número de bytes; strings usam o formato de ParseSize e
+// números inteiros são interpretados como bytes
+func (c *Config) GetSize(path string) (int64, bool) {
+	value, ok := c.Get(path)
+	if !ok {
+		return 0, false
+	}
+
+	switch v := value.(type) {
+	case string:
+		size, err := ParseSize(v)
+		if err != nil {
+			return 0, false
+		}
+		return size, true
+	case float64:
+		if v != math.Trunc(v) || v < 0 {
+			return 0, false
+		}
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// Coerce converte no próprio Config os valores para o tipo esperado, de modo que Get, GetAll
+// e Map já os retornem tipados. hints associa padrões de caminho (sintaxe de GetAll, ex.:
+// "services.*.timeout") ao tipo; sem hints, todos os valores textuais são reconhecidos pela
+// forma (ValueAuto) e os demais são mantidos. Valores indicados em hints que não podem ser
+// convertidos resultam em erro.
+func (c *Config) Coerce(hints map[string]ValueType) error {
+	if len(hints) == 0 {
+		c.data = coerceTree(c.data).(map[string]interface{})
+		return nil
+	}
+
+	patterns := make([]string, 0, len(hints))
+	for pattern := range hints {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	var errs []error
+	for _, pattern := range patterns {
+		for _, match := range c.GetAll(pattern) {
+			value, err := coerceMatch(match.Value, hints[pattern])
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %v", ErrCoercionFailed, match.Path, err))
+				continue
+			}
+			c.set(match.Segments, value)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// coerceTree converte recursivamente os valores textuais reconhecidos pela forma
+func coerceTree(node interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = coerceTree(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = coerceTree(child)
+		}
+		return v
+	case string:
+		if value, err := coerceValue(v, ValueAuto); err == nil {
+			return value
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// coerceMatch converte o valor encontrado para o tipo indicado. Os tipos escalares aceitam
+// números e booleanos; os demais convertem apenas texto e mantêm valores já tipados.
+func coerceMatch(value interface{}, valueType ValueType) (interface{}, error) {
+	switch valueType {
+	case ValueString, ValueInt, ValueFloat, ValueBool:
+		return coerceScalar(value, valueType)
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	return coerceValue(text, valueType)
+}
+
+// coerceScalar converte um valor escalar para texto, inteiro, número ou booleano
+func coerceScalar(value interface{}, valueType ValueType) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		trimmed := strings.TrimSpace(v)
+		switch valueType {
+		case ValueString:
+			return v, nil
+		case ValueInt:
+			return strconv.ParseInt(trimmed, 10, 64)
+		case ValueFloat:
+			return strconv.ParseFloat(trimmed, 64)
+		case ValueBool:
+			return strconv.ParseBool(trimmed)
+		}
+	case float64:
+		switch valueType {
+		case ValueString:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case ValueInt:
+			if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+				return nil, fmt.Errorf("%v não é um inteiro", v)
+			}
+			return int64(v), nil
+		case ValueFloat:
+			return v, nil
+		}
+	case int64:
+		switch valueType {
+		case ValueString:
+			return strconv.FormatInt(v, 10), nil
+		case ValueInt:
+			return v, nil
+		case ValueFloat:
+			return float64(v), nil
+		}
+	case bool:
+		switch valueType {
+		case ValueString:
+			return strconv.FormatBool(v), nil
+		case ValueBool:
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%s não pode ser convertido para %s", describeValue(value), valueType)
+}
+
+// describeValue descreve o tipo do valor nas mensagens de erro
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nulo"
+	case map[string]interface{}:
+		return "objeto"
+	case []interface{}:
+		return "lista"
+	case string:
+		return fmt.Sprintf("texto %q", v)
+	default:
+		return fmt.Sprintf("%T %v", v, v)
+	}
+}
+
+// coerceValue converte o texto para o tipo indicado; com ValueAuto, o texto que não
+// corresponde a nenhuma forma conhecida é retornado sem alteração
+func coerceValue(text string, valueType ValueType) (interface{}, error) {
+	trimmed := strings.TrimSpace(text)
+	switch valueType {
+	case ValueDuration:
+		return time.ParseDuration(trimmed)
+	case ValueTime:
+		return time.Parse(time.RFC3339, trimmed)
+	case ValueSize:
+		return ParseSize(trimmed)
+	case ValueString, ValueInt, ValueFloat, ValueBool:
+		return coerceScalar(text, valueType)
+	case ValueAuto:
+		if durationPattern.MatchString(trimmed) {
+			return time.ParseDuration(trimmed)
+		}
+		if sizePattern.MatchString(strings.ToUpper(trimmed)) {
+			return ParseSize(trimmed)
+		}
+		if parsed, err := time.Parse(time.RFC3339, trimmed); err == nil {
+			return parsed, nil
+		}
+		return text, nil
+	default:
+		return nil, fmt.Errorf("tipo desconhecido: %s", valueType)
+	}
+}
+
+// set grava o valor no caminho concreto (Match.Segments de GetAll), que deve existir
+func (c *Config) set(segments []string, value interface{}) {
+	var current interface{} = c.data
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch node := current.(type) {
+		case map[string]interface{}:
+			if last {
+				node[segment] = value
+				return
+			}
+			current = node[segment]
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return
+			}
+			if last {
+				node[index] = value
+				return
+			}
+			current = node[index]
+		default:
+			return
+		}
+	}
+}
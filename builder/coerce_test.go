@@ -0,0 +1,47 @@
+package builder_test
+
+import (
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "0", want: 0},
+		{value: "1024", want: 1024},
+		{value: "512B", want: 512},
+		{value: "1KB", want: 1000},
+		{value: "1.5GB", want: 1500000000},
+		{value: "2 MB", want: 2000000},
+		{value: "1KiB", want: 1024},
+		{value: "512MiB", want: 536870912},
+		{value: "0.5KiB", want: 512},
+		{value: "1.5gib", want: 1610612736},
+		{value: "1PiB", want: 1 << 50},
+		{value: "8191PiB", want: 8191 << 50},
+		{value: "8192PiB", wantErr: true},
+		{value: "10000PB", wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "-1KB", wantErr: true},
+		{value: "1XB", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := builder.ParseSize(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSize(%q) = %d, esperado erro", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v; esperado %d", tt.value, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Condition decide se as chaves que correspondem a um padrão de Conditions são incluídas no
+// documento final, permitindo que uma única árvore de parâmetros atenda a vários destinos
+// de implantação. Com Tags e When informados, ambos precisam ser atendidos.
+type Condition struct {
+	// Tags exige que o parâmetro de origem da chave tenha as tags com esses valores (ex.:
+	// env=prod). Parâmetros sem a tag são mantidos, por serem comuns a todos os destinos.
+	// Em subárvores, cada parâmetro de origem é avaliado isoladamente.
+	Tags map[string]string
+
+	// When avalia uma expressão sobre a configuração montada (ex.: ValueEquals("env", "prod")).
+	// Todas as condições são avaliadas sobre a configuração anterior às remoções.
+	When func(config *Config) bool
+}
+
+// ValueEquals retorna uma expressão para Condition.When atendida quando o valor do caminho,
+// formatado como texto, é igual a value
+func ValueEquals(path, value string) func(config *Config) bool {
+	return func(config *Config) bool {
+		current, ok := config.GetString(path)
+		return ok && current == value
+	}
+}
+
+// ValueIn retorna uma expressão para Condition.When atendida quando o valor do caminho,
+// formatado como texto, é um dos valores informados
+func ValueIn(path string, values ...string) func(config *Config) bool {
+	return func(config *Config) bool {
+		current, ok := config.GetString(path)
+		if !ok {
+			return false
+		}
+		for _, value := range values {
+			if current == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// applyConditions remove da configuração montada as chaves cujas condições não são
+// atendidas. Os padrões usam a sintaxe de Config.GetAll.
+func (b *ConfigBuilder) applyConditions(ctx context.Context, asm *assembly, opts BuildOptions) error {
+	config := &Config{data: asm.config, caseInsensitive: opts.CaseInsensitiveKeys}
+
+	patterns := make([]string, 0, len(opts.Conditions))
+	for pattern := range opts.Conditions {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	tagCache := make(map[string]map[string]string)
+	excluded := make(map[string]bool)
+	for _, pattern := range patterns {
+		condition := opts.Conditions[pattern]
+		satisfied := condition.When == nil || condition.When(config)
+		for _, match := range config.GetAll(pattern) {
+			if !satisfied {
+				excluded[match.Path] = true
+				continue
+			}
+			if len(condition.Tags) == 0 {
+				continue
+			}
+
+			paths, err := b.excludedByTags(ctx, asm, match.Path, condition.Tags, tagCache)
+			if err != nil {
+				return err
+			}
+			for _, path := range paths {
+				excluded[path] = true
+			}
+		}
+	}
+
+	if len(excluded) > 0 {
+		pruned, _ := pruneExcluded(asm.config, "", excluded)
+		asm.config = pruned.(map[string]interface{})
+	}
+	return nil
+}
+
+// excludedByTags retorna os caminhos (sintaxe de GetAll) originados de parâmetros sob a
+// chave cujas tags divergem das exigidas. As tags são consultadas com o cliente do prefixo
+// de origem de cada parâmetro.
+func (b *ConfigBuilder) excludedByTags(ctx context.Context, asm *assembly, matchPath string, required map[string]string, cache map[string]map[string]string) ([]string, error) {
+	owners := asm.owners
+	ownerPath := strings.Join(splitPath(matchPath), "/")
+
+	keys := make([]string, 0, 1)
+	for key := range owners {
+		if key == ownerPath || strings.HasPrefix(key, ownerPath+"/") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var paths []string
+	for _, key := range keys {
+		name := owners[key]
+		tags, ok := cache[name]
+		if !ok {
+			var err error
+			tags, err = b.listTags(asm.contextOf(ctx, name), name)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao buscar tags do parâmetro %s: %w", name, err)
+			}
+			cache[name] = tags
+		}
+
+		for tagKey, tagValue := range required {
+			if value, exists := tags[tagKey]; exists && value != tagValue {
+				paths = append(paths, joinPath(strings.Split(key, "/")))
+				break
+			}
+		}
+	}
+	return paths, nil
+}
+
+// pruneExcluded reconstrói a árvore sem os caminhos excluídos. Objetos e listas que ficam
+// vazios pelas remoções também são removidos; o retorno indica esse caso.
+func pruneExcluded(node interface{}, path string, excluded map[string]bool) (interface{}, bool) {
+	childPath := func(key string) string {
+		if path == "" {
+			return EscapeKey(key)
+		}
+		return path + "." + EscapeKey(key)
+	}
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return v, false
+		}
+		for key, child := range v {
+			pruned, emptied := pruneExcluded(child, childPath(key), excluded)
+			if excluded[childPath(key)] || emptied {
+				delete(v, key)
+				continue
+			}
+			v[key] = pruned
+		}
+		return v, len(v) == 0
+	case []interface{}:
+		if len(v) == 0 {
+			return v, false
+		}
+		kept := v[:0]
+		for i, child := range v {
+			pruned, emptied := pruneExcluded(child, childPath(strconv.Itoa(i)), excluded)
+			if excluded[childPath(strconv.Itoa(i))] || emptied {
+				continue
+			}
+			kept = append(kept, pruned)
+		}
+		return kept, len(kept) == 0
+	default:
+		return node, false
+	}
+}
@@ -0,0 +1,132 @@
+package builder_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+// conditionsFake cria o Parameter Store simulado com parâmetros marcados por ambiente
+func conditionsFake() *ssmtest.Client {
+	fake := ssmtest.New()
+	fake.Put(ssmtest.Parameter{Name: "/app/env", Value: "dev"})
+	fake.Put(ssmtest.Parameter{Name: "/app/debug/verbose", Value: "true"})
+	fake.Put(ssmtest.Parameter{Name: "/app/hosts", Value: `["a", "b"]`})
+	fake.Put(ssmtest.Parameter{Name: "/app/db/host", Value: "prod-db", Tags: map[string]string{"env": "prod"}})
+	fake.Put(ssmtest.Parameter{Name: "/app/db/pool/size", Value: "10"})
+	fake.Put(ssmtest.Parameter{Name: "/app/features/beta/enabled", Value: "true", Tags: map[string]string{"env": "dev"}})
+	fake.Put(ssmtest.Parameter{Name: "/app/features/search/enabled", Value: "true", Tags: map[string]string{"env": "prod", "team": "x"}})
+	return fake
+}
+
+func TestConditions(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions map[string]builder.Condition
+		want       map[string]interface{}
+	}{
+		{
+			name:       "When não atendida",
+			conditions: map[string]builder.Condition{"debug": {When: builder.ValueEquals("env", "prod")}},
+			want: map[string]interface{}{
+				"env":      "dev",
+				"hosts":    []interface{}{"a", "b"},
+				"db":       map[string]interface{}{"host": "prod-db", "pool": map[string]interface{}{"size": float64(10)}},
+				"features": map[string]interface{}{"beta": map[string]interface{}{"enabled": true}, "search": map[string]interface{}{"enabled": true}},
+			},
+		},
+		{
+			name:       "When atendida com ValueIn",
+			conditions: map[string]builder.Condition{"debug": {When: builder.ValueIn("env", "dev", "qa")}},
+			want: map[string]interface{}{
+				"env":      "dev",
+				"hosts":    []interface{}{"a", "b"},
+				"debug":    map[string]interface{}{"verbose": true},
+				"db":       map[string]interface{}{"host": "prod-db", "pool": map[string]interface{}{"size": float64(10)}},
+				"features": map[string]interface{}{"beta": map[string]interface{}{"enabled": true}, "search": map[string]interface{}{"enabled": true}},
+			},
+		},
+		{
+			name:       "Tags em subárvore mantém parâmetros sem a tag",
+			conditions: map[string]builder.Condition{"db": {Tags: map[string]string{"env": "dev"}}},
+			want: map[string]interface{}{
+				"env":      "dev",
+				"hosts":    []interface{}{"a", "b"},
+				"debug":    map[string]interface{}{"verbose": true},
+				"db":       map[string]interface{}{"pool": map[string]interface{}{"size": float64(10)}},
+				"features": map[string]interface{}{"beta": map[string]interface{}{"enabled": true}, "search": map[string]interface{}{"enabled": true}},
+			},
+		},
+		{
+			name:       "Tags com curinga e objetos vazios removidos",
+			conditions: map[string]builder.Condition{"features.*": {Tags: map[string]string{"env": "prod"}}},
+			want: map[string]interface{}{
+				"env":      "dev",
+				"hosts":    []interface{}{"a", "b"},
+				"debug":    map[string]interface{}{"verbose": true},
+				"db":       map[string]interface{}{"host": "prod-db", "pool": map[string]interface{}{"size": float64(10)}},
+				"features": map[string]interface{}{"search": map[string]interface{}{"enabled": true}},
+			},
+		},
+		{
+			name:       "Tags e When precisam ser atendidas",
+			conditions: map[string]builder.Condition{"features": {Tags: map[string]string{"env": "prod"}, When: builder.ValueEquals("env", "prod")}},
+			want: map[string]interface{}{
+				"env":   "dev",
+				"hosts": []interface{}{"a", "b"},
+				"debug": map[string]interface{}{"verbose": true},
+				"db":    map[string]interface{}{"host": "prod-db", "pool": map[string]interface{}{"size": float64(10)}},
+			},
+		},
+		{
+			name: "lista esvaziada é removida",
+			conditions: map[string]builder.Condition{
+				"hosts.*": {When: builder.ValueEquals("env", "prod")},
+				"env":     {When: builder.ValueEquals("env", "prod")},
+			},
+			want: map[string]interface{}{
+				"debug":    map[string]interface{}{"verbose": true},
+				"db":       map[string]interface{}{"host": "prod-db", "pool": map[string]interface{}{"size": float64(10)}},
+				"features": map[string]interface{}{"beta": map[string]interface{}{"enabled": true}, "search": map[string]interface{}{"enabled": true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := builder.New(conditionsFake()).BuildMap(context.Background(), builder.BuildOptions{
+				Prefixes:    []string{"/app"},
+				StripPrefix: true,
+				Conditions:  tt.conditions,
+			})
+			if err != nil {
+				t.Fatalf("BuildMap: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("configuração = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionsTagLookups(t *testing.T) {
+	fake := conditionsFake()
+	_, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app"},
+		StripPrefix: true,
+		Conditions: map[string]builder.Condition{
+			"features":   {Tags: map[string]string{"env": "prod"}},
+			"features.*": {Tags: map[string]string{"team": "x"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildMap: %v", err)
+	}
+	// As tags de cada parâmetro são consultadas uma única vez entre as condições
+	if calls := fake.Calls("ListTagsForResource"); calls != 2 {
+		t.Errorf("ListTagsForResource chamado %d vezes, esperado 2", calls)
+	}
+}
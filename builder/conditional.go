@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ErrNotModified indica que os parâmetros não mudaram desde o hash informado
+var ErrNotModified = errors.New("configuração não modificada")
+
+// fetchResult resultado da busca antecipada de um prefixo
+type fetchResult struct {
+	params []types.Parameter
+	err    error
+}
+
+// BuildIfModified busca os parâmetros e compara o hash do conteúdo (nome, versão e valor)
+// com previousHash. Quando nada mudou, retorna ErrNotModified sem montar nem serializar o
+// documento; caso contrário, constrói a configuração reaproveitando os parâmetros já buscados.
+// O hash retornado deve ser informado na próxima chamada, com as mesmas opções.
+func (b *ConfigBuilder) BuildIfModified(ctx context.Context, opts BuildOptions, previousHash string) ([]byte, string, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	// Os resultados são indexados pelo escopo do cliente e pelo prefixo, pois o mesmo path
+	// pode ser buscado em contas diferentes
+	results := make(map[string]fetchResult)
+	resultKey := func(ctx context.Context, prefix string) string {
+		return b.clientScope(ctx) + "|" + prefix
+	}
+	var all []types.Parameter
+	for _, spec := range b.resolvePrefixes(opts) {
+		prefixCtx, err := b.withPrefixClient(ctx, spec, opts)
+		var params []types.Parameter
+		if err == nil {
+			params, err = b.fetchPrefix(prefixCtx, spec.path, opts)
+		}
+		if err != nil && !opts.BestEffort {
+			return nil, "", fmt.Errorf("erro ao buscar parâmetros do prefixo %s: %w", spec.path, err)
+		}
+		results[resultKey(prefixCtx, spec.path)] = fetchResult{params: params, err: err}
+		all = append(all, params...)
+	}
+
+	hash := b.contentHash(all)
+	if previousHash != "" && hash == previousHash {
+		return nil, hash, ErrNotModified
+	}
+
+	data, err := b.build(ctx, opts, func(ctx context.Context, prefix string) ([]types.Parameter, error) {
+		result := results[resultKey(ctx, prefix)]
+		return result.params, result.err
+	})
+	return data, hash, err
+}
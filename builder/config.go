@@ -0,0 +1,329 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config acesso tipado à configuração montada. Os caminhos usam "." como separador
+// (ex.: "database.port") e aceitam índices numéricos em listas (ex.: "items.0.name").
+// Pontos e barras invertidas que fazem parte de uma chave são escapados com "\" (ex.:
+// `svc.config\.timeout` para a chave "config.timeout"; ver EscapeKey).
+// Todos os métodos Get retornam o valor zero e false quando o caminho não existe ou o
+// valor não pode ser convertido para o tipo solicitado.
+type Config struct {
+	data map[string]interface{}
+
+	// caseInsensitive compara as chaves sem diferenciar maiúsculas/minúsculas
+	caseInsensitive bool
+}
+
+// NewConfig cria um Config a partir de um mapa já montado
+func NewConfig(data map[string]interface{}) *Config {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	return &Config{data: data}
+}
+
+// Load constrói a configuração e retorna o acesso tipado a ela.
+// No modo BestEffort, retorna o Config parcial junto do *PartialError.
+func (b *ConfigBuilder) Load(ctx context.Context, opts BuildOptions) (*Config, error) {
+	data, err := b.BuildMap(ctx, opts)
+	if data == nil {
+		return nil, err
+	}
+	config := NewConfig(data)
+	config.caseInsensitive = opts.CaseInsensitiveKeys
+	return config, err
+}
+
+// Map retorna o mapa subjacente
+func (c *Config) Map() map[string]interface{} {
+	return c.data
+}
+
+// Get retorna o valor bruto do caminho
+func (c *Config) Get(path string) (interface{}, bool) {
+	var current interface{} = c.data
+	if path == "" {
+		return current, true
+	}
+
+	for _, segment := range splitPath(path) {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			_, value, ok := c.lookup(node, segment)
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// GetString retorna o valor como string; números, booleanos, durações e datas são formatados
+func (c *Config) GetString(path string) (string, bool) {
+	value, ok := c.Get(path)
+	if !ok {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64, int, int64, bool:
+		return fmt.Sprint(v), true
+	case time.Duration:
+		return v.String(), true
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	default:
+		return "", false
+	}
+}
+
+// GetInt retorna o valor como int; aceita números inteiros e strings numéricas
+func (c *Config) GetInt(path string) (int, bool) {
+	value, ok := c.Get(path)
+	if !ok {
+		return 0, false
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if v != float64(int(v)) {
+			return 0, false
+		}
+		return int(v), true
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, false
+		}
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// GetBool retorna o valor como bool; aceita booleanos e strings como "true" e "false"
+func (c *Config) GetBool(path string) (bool, bool) {
+	value, ok := c.Get(path)
+	if !ok {
+		return false, false
+	}
+
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, false
+		}
+		return parsed, true
+	default:
+		return false, false
+	}
+}
+
+// GetDuration retorna o valor como time.Duration; strings usam o formato de
+// time.ParseDuration (ex.: "30s") e números são interpretados como segundos
+func (c *Config) GetDuration(path string) (time.Duration, bool) {
+	value, ok := c.Get(path)
+	if !ok {
+		return 0, false
+	}
+
+	switch v := value.(type) {
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return 0, false
+		}
+		return d, true
+	case float64:
+		return time.Duration(v * float64(time.Second)), true
+	case int:
+		return time.Duration(v) * time.Second, true
+	case time.Duration:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// GetStringSlice retorna uma lista cujos elementos são formatados como string
+func (c *Config) GetStringSlice(path string) ([]string, bool) {
+	value, ok := c.Get(path)
+	if !ok {
+		return nil, false
+	}
+
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if _, nested := item.(map[string]interface{}); nested {
+				return nil, false
+			}
+			result = append(result, fmt.Sprint(item))
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+// Sub retorna a seção do caminho como um novo Config, vazio quando a seção não existe
+// ou não é um objeto
+func (c *Config) Sub(path string) *Config {
+	value, ok := c.Get(path)
+	if !ok {
+		return NewConfig(nil)
+	}
+	section, ok := value.(map[string]interface{})
+	if !ok {
+		return NewConfig(nil)
+	}
+	return &Config{data: section, caseInsensitive: c.caseInsensitive}
+}
+
+// lookup busca a chave no objeto, ignorando maiúsculas/minúsculas quando configurado,
+// e retorna também a chave efetivamente encontrada
+func (c *Config) lookup(node map[string]interface{}, key string) (string, interface{}, bool) {
+	if value, ok := node[key]; ok || !c.caseInsensitive {
+		return key, value, ok
+	}
+	for candidate, value := range node {
+		if strings.EqualFold(candidate, key) {
+			return candidate, value, true
+		}
+	}
+	return "", nil, false
+}
+
+// Match valor encontrado por GetAll, com o caminho concreto onde foi encontrado
+type Match struct {
+	Path     string   // Caminho concreto, com as chaves escapadas por EscapeKey
+	Segments []string // Chaves e índices do caminho, sem escape
+	Value    interface{}
+}
+
+// GetAll retorna todos os valores que correspondem ao padrão, em ordem estável. O segmento
+// "*" corresponde a qualquer chave de objeto ou índice de lista naquele nível
+// (ex.: "services.*.endpoint").
+func (c *Config) GetAll(pattern string) []Match {
+	var segments []string
+	if pattern != "" {
+		segments = splitPath(pattern)
+	}
+
+	var matches []Match
+	c.collectMatches(c.data, segments, nil, &matches)
+	return matches
+}
+
+// collectMatches percorre a árvore acumulando os valores que correspondem aos segmentos
+func (c *Config) collectMatches(current interface{}, segments, path []string, matches *[]Match) {
+	if len(segments) == 0 {
+		segments := append([]string(nil), path...)
+		*matches = append(*matches, Match{Path: joinPath(segments), Segments: segments, Value: current})
+		return
+	}
+
+	segment, rest := segments[0], segments[1:]
+	switch node := current.(type) {
+	case map[string]interface{}:
+		if segment != "*" {
+			if key, value, ok := c.lookup(node, segment); ok {
+				c.collectMatches(value, rest, append(path, key), matches)
+			}
+			return
+		}
+
+		keys := make([]string, 0, len(node))
+		for key := range node {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			c.collectMatches(node[key], rest, append(path, key), matches)
+		}
+	case []interface{}:
+		if segment != "*" {
+			index, err := strconv.Atoi(segment)
+			if err == nil && index >= 0 && index < len(node) {
+				c.collectMatches(node[index], rest, append(path, segment), matches)
+			}
+			return
+		}
+
+		for i, value := range node {
+			c.collectMatches(value, rest, append(path, strconv.Itoa(i)), matches)
+		}
+	}
+}
+
+// EscapeKey escapa "\" e "." da chave para uso em um caminho de Get, GetAll e das opções
+// que usam a mesma sintaxe (TypeSchema, KeyRemaps e Conditions)
+func EscapeKey(key string) string {
+	if !strings.ContainsAny(key, `.\`) {
+		return key
+	}
+	key = strings.ReplaceAll(key, `\`, `\\`)
+	return strings.ReplaceAll(key, ".", `\.`)
+}
+
+// joinPath monta o caminho com ".", escapando as chaves
+func joinPath(segments []string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = EscapeKey(segment)
+	}
+	return strings.Join(escaped, ".")
+}
+
+// splitPath separa o caminho nos "." sem escape, removendo o escape das chaves
+func splitPath(path string) []string {
+	if !strings.Contains(path, `\`) {
+		return strings.Split(path, ".")
+	}
+
+	var segments []string
+	var current strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '\\' && i+1 < len(path):
+			i++
+			current.WriteByte(path[i])
+		case c == '.':
+			segments = append(segments, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	return append(segments, current.String())
+}
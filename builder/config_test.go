@@ -0,0 +1,76 @@
+package builder_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+)
+
+func TestConfigGetEscapedKeys(t *testing.T) {
+	config := builder.NewConfig(map[string]interface{}{
+		"svc": map[string]interface{}{
+			"config.timeout": "30",
+			`dir\name`:       "x",
+			"items":          []interface{}{map[string]interface{}{"name": "a"}},
+		},
+	})
+
+	tests := []struct {
+		path string
+		want interface{}
+		ok   bool
+	}{
+		{path: `svc.config\.timeout`, want: "30", ok: true},
+		{path: "svc." + builder.EscapeKey("config.timeout"), want: "30", ok: true},
+		{path: "svc." + builder.EscapeKey(`dir\name`), want: "x", ok: true},
+		{path: "svc.items.0.name", want: "a", ok: true},
+		{path: "svc.config.timeout", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := config.Get(tt.path)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Get(%q) = %v, %t; esperado %v, %t", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestConfigGetAllSegments(t *testing.T) {
+	config := builder.NewConfig(map[string]interface{}{
+		"svc": map[string]interface{}{
+			"config.timeout": "30",
+			"x":              "1",
+		},
+	})
+
+	matches := config.GetAll("svc.*")
+	if len(matches) != 2 {
+		t.Fatalf("GetAll retornou %d correspondências, esperado 2", len(matches))
+	}
+	want := []builder.Match{
+		{Path: `svc.config\.timeout`, Segments: []string{"svc", "config.timeout"}, Value: "30"},
+		{Path: "svc.x", Segments: []string{"svc", "x"}, Value: "1"},
+	}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("GetAll = %#v, esperado %#v", matches, want)
+	}
+}
+
+func TestConfigCoerceDottedKeys(t *testing.T) {
+	config := builder.NewConfig(map[string]interface{}{
+		"svc": map[string]interface{}{
+			"config.timeout": "30",
+			"x":              "1",
+		},
+	})
+
+	if err := config.Coerce(map[string]builder.ValueType{"svc.*": builder.ValueInt}); err != nil {
+		t.Fatalf("Coerce: %v", err)
+	}
+	want := map[string]int64{`svc.config\.timeout`: 30, "svc.x": 1}
+	for path, expected := range want {
+		if value, _ := config.Get(path); value != expected {
+			t.Errorf("%s = %#v, esperado %d", path, value, expected)
+		}
+	}
+}
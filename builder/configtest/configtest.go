@@ -0,0 +1,258 @@
+// Package configtest oferece helpers de snapshot (golden files) para testar árvores de
+// parâmetros em CI: a configuração é construída a partir de uma fixture em memória e
+// comparada com um arquivo JSON, YAML ou TOML versionado, com ordenação estável e diff
+// legível.
+//
+//	func TestConfig(t *testing.T) {
+//		fixture := configtest.LoadFixture(t, "testdata/params.yaml")
+//		out := configtest.Build(t, fixture, builder.BuildOptions{Prefixes: []string{"/app"}})
+//		configtest.AssertGolden(t, "testdata/app.golden.json", out)
+//	}
+//
+// Para regravar os arquivos golden, execute os testes com UPDATE_GOLDEN=1.
+package configtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+	"gopkg.in/yaml.v3"
+)
+
+// UpdateEnv variável de ambiente que, quando "1" ou "true", regrava os arquivos golden
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// diffContext linhas inalteradas exibidas ao redor de cada diferença
+const diffContext = 3
+
+// Fixture parâmetros de teste, no formato nome -> valor
+type Fixture map[string]string
+
+// LoadFixture lê uma fixture JSON ou YAML (detectada pela extensão) com o nome completo de
+// cada parâmetro e seu valor. Valores que não são texto são serializados como JSON.
+func LoadFixture(tb testing.TB, path string) Fixture {
+	tb.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("erro ao ler a fixture %s: %v", path, err)
+	}
+
+	raw := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		err = json.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		tb.Fatalf("erro ao interpretar a fixture %s: %v", path, err)
+	}
+
+	fixture := make(Fixture, len(raw))
+	for name, value := range raw {
+		if text, ok := value.(string); ok {
+			fixture[name] = text
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			tb.Fatalf("erro ao serializar o parâmetro %s da fixture %s: %v", name, path, err)
+		}
+		fixture[name] = string(encoded)
+	}
+	return fixture
+}
+
+// Client cria um ssmtest.Client populado com a fixture
+func (f Fixture) Client() *ssmtest.Client {
+	client := ssmtest.New()
+	client.Seed(f)
+	return client
+}
+
+// Build constrói a configuração a partir da fixture, encerrando o teste em caso de erro
+func Build(tb testing.TB, fixture Fixture, opts builder.BuildOptions) []byte {
+	tb.Helper()
+
+	out, err := builder.New(fixture.Client()).BuildConfigFromPrefixes(context.Background(), opts)
+	if err != nil {
+		tb.Fatalf("erro ao construir a configuração: %v", err)
+	}
+	return out
+}
+
+// AssertGolden compara got com o arquivo golden, cujo formato é detectado pela extensão
+// (.json, .yaml/.yml ou .toml; outras extensões são comparadas byte a byte). Os dois lados
+// são normalizados com chaves ordenadas antes da comparação, então diferenças apenas de
+// ordem ou de indentação são ignoradas. Com UPDATE_GOLDEN=1 o arquivo é regravado.
+func AssertGolden(tb testing.TB, path string, got []byte) {
+	tb.Helper()
+
+	format, structured := formatOf(path)
+	actual := got
+	if structured {
+		normalized, err := builder.Convert(got, format, format)
+		if err != nil {
+			tb.Fatalf("erro ao normalizar a saída para %s: %v", path, err)
+		}
+		actual = normalized
+	}
+
+	if update() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("erro ao criar o diretório de %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			tb.Fatalf("erro ao gravar o arquivo golden %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("erro ao ler o arquivo golden %s (execute com %s=1 para criá-lo): %v", path, UpdateEnv, err)
+	}
+	if structured {
+		normalized, err := builder.Convert(expected, format, format)
+		if err != nil {
+			tb.Fatalf("erro ao normalizar o arquivo golden %s: %v", path, err)
+		}
+		expected = normalized
+	}
+
+	if string(expected) != string(actual) {
+		tb.Errorf("saída diferente do arquivo golden %s (execute com %s=1 para atualizá-lo):\n%s",
+			path, UpdateEnv, Diff(string(expected), string(actual)))
+	}
+}
+
+// AssertGoldenMap serializa a árvore como JSON e a compara com o arquivo golden
+func AssertGoldenMap(tb testing.TB, path string, got map[string]interface{}) {
+	tb.Helper()
+
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		tb.Fatalf("erro ao serializar a configuração: %v", err)
+	}
+
+	format, structured := formatOf(path)
+	if structured && format != builder.FormatJSON {
+		if encoded, err = builder.Convert(encoded, builder.FormatJSON, format); err != nil {
+			tb.Fatalf("erro ao converter a configuração para %s: %v", path, err)
+		}
+	}
+	AssertGolden(tb, path, encoded)
+}
+
+// Diff retorna as diferenças linha a linha entre want e got no formato unificado
+// ("-" para linhas esperadas, "+" para linhas obtidas), com algumas linhas de contexto.
+// O prefixo e o sufixo comuns são descartados antes do cálculo da maior subsequência
+// comum, que fica restrito ao trecho alterado.
+func Diff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	for _, text := range a[:prefix] {
+		lines = append(lines, line{' ', text})
+	}
+
+	// lcs[i][j] tamanho da maior subsequência comum entre ma[i:] e mb[j:]
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			lines = append(lines, line{' ', ma[i]})
+			i++
+			j++
+		case i < len(ma) && (j == len(mb) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', ma[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', mb[j]})
+			j++
+		}
+	}
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, line{' ', text})
+	}
+
+	// marca as linhas próximas de alguma diferença
+	visible := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for n := max(0, k-diffContext); n <= min(len(lines)-1, k+diffContext); n++ {
+			visible[n] = true
+		}
+	}
+
+	var sb strings.Builder
+	for k, l := range lines {
+		if !visible[k] {
+			if k > 0 && visible[k-1] {
+				sb.WriteString("  ...\n")
+			}
+			continue
+		}
+		fmt.Fprintf(&sb, "%c %s\n", l.op, l.text)
+	}
+	return sb.String()
+}
+
+// formatOf detecta o formato do arquivo golden pela extensão
+func formatOf(path string) (builder.Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return builder.FormatJSON, true
+	case ".yaml", ".yml":
+		return builder.FormatYAML, true
+	case ".toml":
+		return builder.FormatTOML, true
+	default:
+		return "", false
+	}
+}
+
+// update indica se os arquivos golden devem ser regravados
+func update() bool {
+	value := strings.ToLower(os.Getenv(UpdateEnv))
+	return value == "1" || value == "true"
+}
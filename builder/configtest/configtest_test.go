@@ -0,0 +1,229 @@
+package configtest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/configtest"
+)
+
+// recorder testing.TB que registra as falhas em vez de encerrar o teste
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+	panic(r)
+}
+
+// run executa fn com um recorder e retorna as falhas registradas
+func run(t *testing.T, fn func(tb testing.TB)) (failures []string) {
+	r := &recorder{TB: t}
+	defer func() {
+		if p := recover(); p != nil && p != r {
+			panic(p)
+		}
+		failures = r.failures
+	}()
+	fn(r)
+	return r.failures
+}
+
+// writeFile grava o conteúdo no arquivo, encerrando o teste em caso de erro
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestAssertGoldenNormalization(t *testing.T) {
+	t.Setenv(configtest.UpdateEnv, "")
+	dir := t.TempDir()
+
+	tests := []struct {
+		name   string
+		file   string
+		golden string
+		got    string
+		fails  bool
+	}{
+		{
+			name:   "JSON com outra ordem e indentação",
+			file:   "app.json",
+			golden: "{\n    \"db\": {\"port\": 5432, \"host\": \"localhost\"},\n    \"debug\": true\n}\n",
+			got:    `{"debug":true,"db":{"host":"localhost","port":5432}}`,
+		},
+		{
+			name:   "YAML com outra ordem",
+			file:   "app.yaml",
+			golden: "debug: true\ndb:\n  port: 5432\n  host: localhost\n",
+			got:    "db:\n    host: localhost\n    port: 5432\ndebug: true\n",
+		},
+		{
+			name:   "TOML com outra ordem",
+			file:   "app.toml",
+			golden: "debug = true\n\n[db]\nport = 5432\nhost = 'localhost'\n",
+			got:    "debug = true\n[db]\nhost = \"localhost\"\nport = 5432\n",
+		},
+		{
+			name:   "valor diferente",
+			file:   "diff.json",
+			golden: `{"db": {"host": "localhost", "port": 5432}}`,
+			got:    `{"db": {"host": "db.internal", "port": 5432}}`,
+			fails:  true,
+		},
+		{
+			name:   "extensão desconhecida compara byte a byte",
+			file:   "app.txt",
+			golden: "a: 1\nb: 2\n",
+			got:    "b: 2\na: 1\n",
+			fails:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			writeFile(t, path, tt.golden)
+
+			failures := run(t, func(tb testing.TB) { configtest.AssertGolden(tb, path, []byte(tt.got)) })
+			if tt.fails != (len(failures) > 0) {
+				t.Errorf("falhas = %q, esperado falha: %t", failures, tt.fails)
+			}
+		})
+	}
+}
+
+func TestAssertGoldenMissingFile(t *testing.T) {
+	t.Setenv(configtest.UpdateEnv, "")
+	path := filepath.Join(t.TempDir(), "missing.json")
+
+	failures := run(t, func(tb testing.TB) { configtest.AssertGolden(tb, path, []byte(`{}`)) })
+	if len(failures) != 1 || !strings.Contains(failures[0], configtest.UpdateEnv+"=1") {
+		t.Errorf("falhas = %q, esperado a instrução para criar o arquivo", failures)
+	}
+}
+
+func TestAssertGoldenUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "app.golden.json")
+	got := []byte(`{"debug":true,"db":{"port":5432,"host":"localhost"}}`)
+
+	t.Setenv(configtest.UpdateEnv, "1")
+	configtest.AssertGolden(t, path, got)
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("arquivo golden não gravado: %v", err)
+	}
+	want, _ := builder.Convert(got, builder.FormatJSON, builder.FormatJSON)
+	if string(written) != string(want) {
+		t.Errorf("arquivo golden = %s, esperado a saída normalizada %s", written, want)
+	}
+
+	t.Setenv(configtest.UpdateEnv, "")
+	if failures := run(t, func(tb testing.TB) { configtest.AssertGolden(tb, path, got) }); len(failures) > 0 {
+		t.Errorf("comparação com o arquivo regravado falhou: %q", failures)
+	}
+}
+
+func TestBuildFromFixture(t *testing.T) {
+	t.Setenv(configtest.UpdateEnv, "")
+	dir := t.TempDir()
+	fixturePath := filepath.Join(dir, "params.yaml")
+	writeFile(t, fixturePath, "/app/db/host: localhost\n/app/db/pool/size: 10\n/app/features: [a, b]\n")
+	goldenPath := filepath.Join(dir, "app.golden.json")
+	writeFile(t, goldenPath, `{"features": ["a", "b"], "db": {"pool": {"size": 10}, "host": "localhost"}}`)
+
+	fixture := configtest.LoadFixture(t, fixturePath)
+	if fixture["/app/db/pool/size"] != "10" || fixture["/app/features"] != `["a","b"]` {
+		t.Errorf("fixture = %v, esperado valores serializados como JSON", fixture)
+	}
+
+	out := configtest.Build(t, fixture, builder.BuildOptions{Prefixes: []string{"/app"}, StripPrefix: true})
+	configtest.AssertGolden(t, goldenPath, out)
+}
+
+func TestDiff(t *testing.T) {
+	numbered := func(from, to int, replace map[int]string) string {
+		var sb strings.Builder
+		for n := from; n <= to; n++ {
+			if text, ok := replace[n]; ok {
+				sb.WriteString(text + "\n")
+				continue
+			}
+			fmt.Fprintf(&sb, "l%d\n", n)
+		}
+		return sb.String()
+	}
+
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff string
+	}{
+		{name: "iguais", want: "a\nb\n", got: "a\nb\n", diff: ""},
+		{
+			name: "linha alterada com contexto",
+			want: numbered(1, 10, nil),
+			got:  numbered(1, 10, map[int]string{5: "x"}),
+			diff: "  l2\n  l3\n  l4\n- l5\n+ x\n  l6\n  l7\n  l8\n  ...\n",
+		},
+		{
+			name: "linha inserida no fim",
+			want: "a\nb\n",
+			got:  "a\nb\nc\n",
+			diff: "  a\n  b\n+ c\n",
+		},
+		{
+			name: "linha removida no início",
+			want: "a\nb\nc\n",
+			got:  "b\nc\n",
+			diff: "- a\n  b\n  c\n",
+		},
+		{
+			name: "duas diferenças distantes",
+			want: numbered(1, 12, nil),
+			got:  numbered(1, 12, map[int]string{1: "x", 12: "y"}),
+			diff: "- l1\n+ x\n  l2\n  l3\n  l4\n  ...\n  l9\n  l10\n  l11\n- l12\n+ y\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := configtest.Diff(tt.want, tt.got); got != tt.diff {
+				t.Errorf("Diff =\n%s\nesperado\n%s", got, tt.diff)
+			}
+		})
+	}
+}
+
+// TestDiffLargeInput garante que arquivos grandes com poucas alterações não alocam a
+// matriz completa da subsequência comum
+func TestDiffLargeInput(t *testing.T) {
+	lines := make([]string, 200000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	want := strings.Join(lines, "\n")
+	lines[100000] = "changed"
+	got := strings.Join(lines, "\n")
+
+	diff := configtest.Diff(want, got)
+	if !strings.Contains(diff, "- line 100000\n+ changed\n") {
+		t.Errorf("Diff não contém a alteração:\n%s", diff)
+	}
+}
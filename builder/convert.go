@@ -0,0 +1,125 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Format formato de serialização suportado por Convert
+type Format string
+
+const (
+	// FormatJSON JSON (RFC 8259)
+	FormatJSON Format = "json"
+	// FormatYAML YAML 1.2
+	FormatYAML Format = "yaml"
+	// FormatTOML TOML 1.0
+	FormatTOML Format = "toml"
+)
+
+// ErrUnsupportedFormat indica um formato não suportado por Convert
+var ErrUnsupportedFormat = errors.New("formato não suportado")
+
+// Convert converte um artefato já gerado entre JSON, YAML e TOML, sem buscar os parâmetros
+// novamente. As chaves são emitidas em ordem alfabética e o JSON é indentado como no
+// JSONOutput. Números inteiros são preservados como inteiros; TOML não suporta valores nulos.
+func Convert(in []byte, from, to Format) ([]byte, error) {
+	var document interface{}
+	switch from {
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(in))
+		decoder.UseNumber()
+		if err := decoder.Decode(&document); err != nil {
+			return nil, fmt.Errorf("erro ao ler JSON: %w", err)
+		}
+		document = normalizeNumbers(document)
+	case FormatYAML:
+		if err := yaml.Unmarshal(in, &document); err != nil {
+			return nil, fmt.Errorf("erro ao ler YAML: %w", err)
+		}
+	case FormatTOML:
+		var table map[string]interface{}
+		if err := toml.Unmarshal(in, &table); err != nil {
+			return nil, fmt.Errorf("erro ao ler TOML: %w", err)
+		}
+		document = table
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, from)
+	}
+
+	switch to {
+	case FormatJSON:
+		return marshalJSON(document, BuildOptions{JSONOutput: true})
+	case FormatYAML:
+		return yaml.Marshal(document)
+	case FormatTOML:
+		table, ok := document.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("TOML requer um objeto na raiz do documento")
+		}
+		if path := findNull(table, ""); path != "" {
+			return nil, fmt.Errorf("TOML não suporta valores nulos (%s)", path)
+		}
+		return toml.Marshal(table)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, to)
+	}
+}
+
+// normalizeNumbers converte json.Number em int64 quando inteiro e em float64 nos demais casos
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		if math.IsInf(f, 0) {
+			return v.String()
+		}
+		return f
+	default:
+		return v
+	}
+}
+
+// findNull retorna o caminho (separado por ".") do primeiro valor nulo, ou "" se não houver
+func findNull(value interface{}, path string) string {
+	switch v := value.(type) {
+	case nil:
+		if path == "" {
+			return "."
+		}
+		return path
+	case map[string]interface{}:
+		for key, item := range v {
+			if found := findNull(item, strings.TrimPrefix(path+"."+key, ".")); found != "" {
+				return found
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if found := findNull(item, fmt.Sprintf("%s.%d", path, i)); found != "" {
+				return found
+			}
+		}
+	}
+	return ""
+}
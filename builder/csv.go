@@ -0,0 +1,96 @@
+package builder
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// CSVFormat formato de um parâmetro CSV, convertido em uma lista de objetos (um por linha)
+type CSVFormat struct {
+	Header    []string // Nomes das colunas; vazio usa a primeira linha do valor como cabeçalho
+	Delimiter rune     // Separador das colunas (padrão: ",")
+}
+
+// csvFormat retorna o formato CSV do parâmetro, quando ele corresponde a CSVValues ou termina
+// com CSVSuffix. Padrões mais longos (mais específicos) são avaliados primeiro.
+func (b *ConfigBuilder) csvFormat(param types.Parameter, opts BuildOptions) (CSVFormat, bool) {
+	patterns := make([]string, 0, len(opts.CSVValues))
+	for pattern := range opts.CSVValues {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, *param.Name); matched {
+			return opts.CSVValues[pattern], true
+		}
+	}
+	if opts.CSVSuffix != "" && strings.HasSuffix(*param.Name, opts.CSVSuffix) {
+		return CSVFormat{}, true
+	}
+	return CSVFormat{}, false
+}
+
+// parseCSV converte o valor CSV em uma lista de objetos indexados pelo cabeçalho. As células
+// passam pela mesma conversão dos valores (JSON), exceto com RawValues.
+func (b *ConfigBuilder) parseCSV(value string, format CSVFormat, raw bool) ([]interface{}, error) {
+	reader := csv.NewReader(strings.NewReader(value))
+	reader.TrimLeadingSpace = true
+	if format.Delimiter != 0 {
+		reader.Comma = format.Delimiter
+	}
+
+	header := format.Header
+	if len(header) == 0 {
+		first, err := reader.Read()
+		if err == io.EOF {
+			return []interface{}{}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler o cabeçalho CSV: %w", err)
+		}
+		header = first
+	}
+	reader.FieldsPerRecord = len(header)
+
+	seen := make(map[string]bool, len(header))
+	for _, column := range header {
+		if column == "" || seen[column] {
+			return nil, fmt.Errorf("cabeçalho CSV com coluna vazia ou duplicada: %q", column)
+		}
+		seen[column] = true
+	}
+
+	records := []interface{}{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler CSV: %w", err)
+		}
+
+		record := make(map[string]interface{}, len(header))
+		for i, column := range header {
+			if raw {
+				record[column] = row[i]
+			} else {
+				record[column] = b.parseParameterValue(row[i])
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
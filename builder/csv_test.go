@@ -0,0 +1,135 @@
+package builder_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+func TestCSVValues(t *testing.T) {
+	users := []interface{}{
+		map[string]interface{}{"name": "ana", "admin": true, "quota": float64(10)},
+		map[string]interface{}{"name": "bia", "admin": false, "quota": float64(20)},
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		opts  builder.BuildOptions
+		want  interface{}
+	}{
+		{
+			name:  "CSVSuffix com cabeçalho na primeira linha",
+			value: "name,admin,quota\nana,true,10\nbia, false, 20\n",
+			opts:  builder.BuildOptions{CSVSuffix: "/users"},
+			want:  users,
+		},
+		{
+			name:  "CSVValues com cabeçalho e separador",
+			value: "ana;true;10\nbia;false;20",
+			opts:  builder.BuildOptions{CSVValues: map[string]builder.CSVFormat{"/app/*/users": {Header: []string{"name", "admin", "quota"}, Delimiter: ';'}}},
+			want:  users,
+		},
+		{
+			name:  "padrão mais específico tem precedência",
+			value: "ana;true;10\nbia;false;20",
+			opts: builder.BuildOptions{CSVValues: map[string]builder.CSVFormat{
+				"/app/team/*":     {Header: []string{"a", "b", "c"}},
+				"/app/team/users": {Header: []string{"name", "admin", "quota"}, Delimiter: ';'},
+			}},
+			want: users,
+		},
+		{
+			name:  "CSVValues tem precedência sobre CSVSuffix",
+			value: "ana;true;10\nbia;false;20",
+			opts: builder.BuildOptions{
+				CSVSuffix: "/users",
+				CSVValues: map[string]builder.CSVFormat{"/app/team/users": {Header: []string{"name", "admin", "quota"}, Delimiter: ';'}},
+			},
+			want: users,
+		},
+		{
+			name:  "RawValues mantém as células como texto",
+			value: "name,admin,quota\nana,true,10\n",
+			opts:  builder.BuildOptions{CSVSuffix: "/users", RawValues: true},
+			want:  []interface{}{map[string]interface{}{"name": "ana", "admin": "true", "quota": "10"}},
+		},
+		{
+			name:  "apenas cabeçalho",
+			value: "name,admin,quota\n",
+			opts:  builder.BuildOptions{CSVSuffix: "/users"},
+			want:  []interface{}{},
+		},
+		{
+			name:  "campo entre aspas com separador",
+			value: "name,note\nana,\"a, b\"\n",
+			opts:  builder.BuildOptions{CSVSuffix: "/users"},
+			want:  []interface{}{map[string]interface{}{"name": "ana", "note": "a, b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := ssmtest.New()
+			fake.Seed(map[string]string{
+				"/app/team/users": tt.value,
+				"/app/debug/on":   "true",
+			})
+
+			opts := tt.opts
+			opts.Prefixes = []string{"/app"}
+			opts.StripPrefix = true
+			got, err := builder.New(fake).BuildMap(context.Background(), opts)
+			if err != nil {
+				t.Fatalf("BuildMap: %v", err)
+			}
+			team, _ := got["team"].(map[string]interface{})
+			if !reflect.DeepEqual(team["users"], tt.want) {
+				t.Errorf("team.users = %#v, esperado %#v", team["users"], tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVValuesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		msg   string
+	}{
+		{name: "coluna duplicada", value: "name,name\nana,bia\n", msg: "duplicada"},
+		{name: "coluna vazia", value: "name,\nana,x\n", msg: "coluna vazia"},
+		{name: "quantidade de campos", value: "name,admin\nana\n", msg: "erro ao ler CSV"},
+		{name: "aspas não fechadas", value: "name\n\"ana\n", msg: "erro ao ler CSV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := ssmtest.New()
+			fake.Seed(map[string]string{"/app/team/users": tt.value})
+
+			_, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+				Prefixes:    []string{"/app"},
+				StripPrefix: true,
+				CSVSuffix:   "/users",
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.msg) || !strings.Contains(err.Error(), "/app/team/users") {
+				t.Errorf("erro %v, esperado conter %q e o nome do parâmetro", err, tt.msg)
+			}
+		})
+	}
+}
+
+func TestCSVValuesInvalidPattern(t *testing.T) {
+	_, err := builder.New(ssmtest.New()).BuildMap(context.Background(), builder.BuildOptions{
+		Prefixes:  []string{"/app"},
+		CSVValues: map[string]builder.CSVFormat{"/app/[": {}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "CSVValues") {
+		t.Errorf("erro %v, esperado padrão inválido em CSVValues", err)
+	}
+}
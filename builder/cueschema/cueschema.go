@@ -0,0 +1,106 @@
+// Package cueschema valida configurações montadas pelo builder contra definições CUE,
+// que permitem restrições mais fortes que JSON Schema (intervalos, expressões regulares,
+// valores padrão e campos fechados).
+//
+//	v, err := cueschema.New(schema, "#Config")
+//	data, err := svc.BuildConfigFromPrefixes(ctx, builder.BuildOptions{
+//		Prefixes:   []string{"/app"},
+//		Validators: []builder.Validator{v},
+//	})
+//
+// O pacote é um módulo próprio (github.com/raywall/go-libs-config/builder/cueschema),
+// para que as dependências do CUE não sejam exigidas de quem usa apenas o builder.
+package cueschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// ErrInvalidSchema indica que a definição CUE não pôde ser compilada
+var ErrInvalidSchema = errors.New("esquema CUE inválido")
+
+// ParameterGetter operação do Parameter Store usada por FromParameter. *ssm.Client e
+// ssmtest.Client satisfazem a interface.
+type ParameterGetter interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Validator valida a configuração contra uma definição CUE. Implementa builder.Validator
+// e é seguro para uso concorrente.
+type Validator struct {
+	mu     sync.Mutex
+	schema cue.Value
+}
+
+// New compila o esquema CUE. Quando definition é informada (ex.: "#Config"), a
+// configuração é validada contra essa definição; caso contrário, contra o arquivo inteiro.
+func New(schema []byte, definition string) (*Validator, error) {
+	value := cuecontext.New().CompileBytes(schema)
+	if err := value.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSchema, details(err))
+	}
+
+	if definition != "" {
+		value = value.LookupPath(cue.ParsePath(definition))
+		if !value.Exists() {
+			return nil, fmt.Errorf("%w: definição %s não encontrada", ErrInvalidSchema, definition)
+		}
+	}
+
+	return &Validator{schema: value}, nil
+}
+
+// FromParameter carrega o esquema CUE do parâmetro informado do Parameter Store. Como nas
+// construções sem BuildOptions.WithDecryption, o parâmetro não é decifrado: use String.
+func FromParameter(ctx context.Context, client ParameterGetter, name, definition string) (*Validator, error) {
+	output, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name: aws.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar o esquema CUE %s: %w", name, err)
+	}
+	return New([]byte(aws.ToString(output.Parameter.Value)), definition)
+}
+
+// Validate implementa builder.Validator, listando todas as violações do esquema
+func (v *Validator) Validate(ctx context.Context, config map[string]interface{}) error {
+	// cue.Context não é seguro para uso concorrente
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	// A configuração passa por JSON para que números inteiros sejam tratados como int
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("erro ao codificar a configuração: %w", err)
+	}
+	document := v.schema.Context().CompileBytes(data)
+	if err := document.Err(); err != nil {
+		return fmt.Errorf("erro ao carregar a configuração: %s", details(err))
+	}
+
+	value := v.schema.Unify(document)
+	if err := value.Validate(cue.Concrete(true)); err != nil {
+		return fmt.Errorf("esquema CUE: %s", details(err))
+	}
+	return nil
+}
+
+// details lista cada erro CUE com o caminho onde ocorreu
+func details(err error) string {
+	var messages []string
+	for _, e := range cueerrors.Errors(err) {
+		messages = append(messages, e.Error())
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,88 @@
+package cueschema_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/raywall/go-libs-config/builder/cueschema"
+)
+
+const schema = `
+#Config: {
+	port: int & >=1024 & <=65535
+	name: =~"^[a-z][a-z0-9-]*$"
+	debug: bool | *false
+}
+`
+
+// parameters implementação em memória de ParameterGetter
+type parameters map[string]string
+
+func (p parameters) GetParameter(ctx context.Context, input *ssm.GetParameterInput, _ ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	name := aws.ToString(input.Name)
+	value, ok := p[name]
+	if !ok {
+		return nil, &types.ParameterNotFound{Message: aws.String(name)}
+	}
+	return &ssm.GetParameterOutput{Parameter: &types.Parameter{Name: input.Name, Value: aws.String(value)}}, nil
+}
+
+func TestFromParameterValidate(t *testing.T) {
+	v, err := cueschema.FromParameter(context.Background(), parameters{"/app/schema": schema}, "/app/schema", "#Config")
+	if err != nil {
+		t.Fatalf("FromParameter: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		errMsg string
+	}{
+		{name: "válida", config: map[string]interface{}{"port": 8080, "name": "orders-api"}},
+		{name: "abaixo do intervalo", config: map[string]interface{}{"port": 80, "name": "orders-api"}, errMsg: "port"},
+		{name: "acima do intervalo", config: map[string]interface{}{"port": 70000, "name": "orders-api"}, errMsg: "port"},
+		{name: "expressão regular", config: map[string]interface{}{"port": 8080, "name": "Orders_API"}, errMsg: "name"},
+		{name: "campo fechado", config: map[string]interface{}{"port": 8080, "name": "orders-api", "extra": true}, errMsg: "extra"},
+		{name: "campo obrigatório ausente", config: map[string]interface{}{"name": "orders-api"}, errMsg: "port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(context.Background(), tt.config)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("erro %v, esperado uma violação em %s", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestFromParameterErrors(t *testing.T) {
+	client := parameters{"/app/invalid": "#Config: {port: int &"}
+
+	_, err := cueschema.FromParameter(context.Background(), client, "/app/missing", "#Config")
+	var notFound *types.ParameterNotFound
+	if !errors.As(err, &notFound) {
+		t.Errorf("parâmetro ausente: erro %v, esperado ParameterNotFound", err)
+	}
+
+	_, err = cueschema.FromParameter(context.Background(), client, "/app/invalid", "#Config")
+	if !errors.Is(err, cueschema.ErrInvalidSchema) {
+		t.Errorf("esquema inválido: erro %v, esperado ErrInvalidSchema", err)
+	}
+
+	_, err = cueschema.New([]byte(schema), "#Other")
+	if !errors.Is(err, cueschema.ErrInvalidSchema) {
+		t.Errorf("definição ausente: erro %v, esperado ErrInvalidSchema", err)
+	}
+}
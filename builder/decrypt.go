@@ -0,0 +1,32 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// Decryptor decifra valores de parâmetros cifrados fora do Parameter Store (ex.: documentos
+// SOPS). Integrações ficam em subpacotes (ex.: builder/sopsdecrypt).
+type Decryptor interface {
+	// Decrypt retorna o valor decifrado e true, ou false quando o valor não está no
+	// formato tratado pelo decifrador
+	Decrypt(ctx context.Context, name, value string) (string, bool, error)
+}
+
+// decryptValue aplica o primeiro decifrador que reconhece o valor
+func (b *ConfigBuilder) decryptValue(ctx context.Context, param types.Parameter, decryptors []Decryptor) (types.Parameter, error) {
+	for _, decryptor := range decryptors {
+		plaintext, ok, err := decryptor.Decrypt(ctx, *param.Name, *param.Value)
+		if err != nil {
+			return param, &ParameterError{Name: *param.Name, Err: fmt.Errorf("erro ao decifrar o valor: %w", err)}
+		}
+		if ok {
+			param.Value = aws.String(plaintext)
+			return param, nil
+		}
+	}
+	return param, nil
+}
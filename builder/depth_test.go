@@ -0,0 +1,105 @@
+package builder_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+func TestMaxDepthPolicies(t *testing.T) {
+	seed := map[string]string{
+		"/app/prod/api/db/primary/host": "localhost",
+		"/app/prod/api/debug":           "true",
+	}
+
+	tests := []struct {
+		name   string
+		policy builder.DepthPolicy
+		want   map[string]interface{}
+	}{
+		{
+			name:   "DepthFlatten",
+			policy: builder.DepthFlatten,
+			want: map[string]interface{}{
+				"db":    map[string]interface{}{"primary.host": "localhost"},
+				"debug": true,
+			},
+		},
+		{
+			name:   "DepthSkip",
+			policy: builder.DepthSkip,
+			want:   map[string]interface{}{"debug": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := ssmtest.New()
+			fake.Seed(seed)
+
+			got, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+				Prefixes:    []string{"/app/prod/api"},
+				StripPrefix: true,
+				MaxDepth:    2,
+				DepthPolicy: tt.policy,
+			})
+			if err != nil {
+				t.Fatalf("BuildMap: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("configuração = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxDepthError(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/prod/api/db/primary/host": "localhost",
+		"/app/prod/api/debug":           "true",
+	})
+
+	_, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app/prod/api"},
+		StripPrefix: true,
+		MaxDepth:    2,
+	})
+	var paramErr *builder.ParameterError
+	if !errors.As(err, &paramErr) {
+		t.Fatalf("erro %v, esperado *ParameterError", err)
+	}
+	if paramErr.Name != "/app/prod/api/db/primary/host" {
+		t.Errorf("ParameterError.Name = %q, esperado /app/prod/api/db/primary/host", paramErr.Name)
+	}
+}
+
+func TestMaxDepthFlattenEscapedPath(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/prod/api/db/primary/host": "localhost",
+		"/app/prod/api/cache/ttl":       "300",
+		"/app/prod/api/debug":           "true",
+	})
+
+	config, err := builder.New(fake).Load(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app/prod/api"},
+		StripPrefix: true,
+		MaxDepth:    2,
+		DepthPolicy: builder.DepthFlatten,
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if value, ok := config.Get(`db.primary\.host`); !ok || value != "localhost" {
+		t.Errorf("Get(db.primary\\.host) = %v, %t; esperado localhost", value, ok)
+	}
+	if _, ok := config.Get("db.primary.host"); ok {
+		t.Error("Get(db.primary.host) não deveria alcançar a chave achatada")
+	}
+}
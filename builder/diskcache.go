@@ -0,0 +1,159 @@
+package builder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// diskCacheSuffix extensão dos arquivos de entrada do DiskCache
+const diskCacheSuffix = ".cache"
+
+// ErrCorruptCacheEntry indica uma entrada do DiskCache que não passou na verificação de
+// integridade; a entrada é removida e tratada como ausente
+var ErrCorruptCacheEntry = errors.New("entrada de cache corrompida")
+
+// DiskCache implementação de Cache em disco, para que execuções de CLI e processos de vida
+// curta reaproveitem os parâmetros buscados por execuções anteriores. Cada entrada é um
+// arquivo nomeado pelo SHA-256 da chave, com a data de expiração e o SHA-256 do conteúdo,
+// conferido a cada leitura. As gravações são atômicas (arquivo temporário e rename), o que
+// permite o uso concorrente por vários processos.
+//
+// Com BuildOptions.WithDecryption, os valores SecureString são gravados decifrados: os
+// arquivos são criados com permissão 0600 em um diretório 0700, mas o diretório deve ficar
+// em um volume protegido.
+type DiskCache struct {
+	dir string
+}
+
+// diskEntry conteúdo do arquivo de uma entrada
+type diskEntry struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+	Checksum  string    `json:"sha256"`
+	Value     []byte    `json:"value"`
+}
+
+// NewDiskCache cria (se necessário) o diretório do cache. Com dir vazio, usa o
+// subdiretório "go-libs-config" do diretório de cache do usuário (os.UserCacheDir).
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao localizar o diretório de cache do usuário: %w", err)
+		}
+		dir = filepath.Join(base, "go-libs-config")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("erro ao criar o diretório de cache %s: %w", dir, err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Dir retorna o diretório das entradas
+func (c *DiskCache) Dir() string {
+	return c.dir
+}
+
+// Get retorna o valor armazenado, se existir, não estiver expirado e passar na verificação
+// de integridade. Entradas corrompidas são removidas e reportadas com ErrCorruptCacheEntry.
+func (c *DiskCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("erro ao ler a entrada de cache: %w", err)
+	}
+
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key || entry.Checksum != checksum(entry.Value) {
+		os.Remove(path)
+		return nil, false, fmt.Errorf("%w: %s", ErrCorruptCacheEntry, filepath.Base(path))
+	}
+	if !entry.ExpiresAt.IsZero() && time.Now().After(entry.ExpiresAt) {
+		os.Remove(path)
+		return nil, false, nil
+	}
+	return entry.Value, true, nil
+}
+
+// Set armazena o valor; ttl zero mantém a entrada até ser removida
+func (c *DiskCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := diskEntry{Key: key, Checksum: checksum(value), Value: value}
+	if ttl > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl).UTC()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(c.path(key), data, 0o600); err != nil {
+		return fmt.Errorf("erro ao gravar a entrada de cache: %w", err)
+	}
+	return nil
+}
+
+// Delete remove a entrada
+func (c *DiskCache) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(c.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("erro ao remover a entrada de cache: %w", err)
+	}
+	return nil
+}
+
+// Prune remove as entradas expiradas ou corrompidas, retornando quantas foram removidas
+func (c *DiskCache) Prune(ctx context.Context) (int, error) {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao listar o diretório de cache %s: %w", c.dir, err)
+	}
+
+	removed := 0
+	now := time.Now()
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		if file.IsDir() || !strings.HasSuffix(file.Name(), diskCacheSuffix) {
+			continue
+		}
+
+		path := filepath.Join(c.dir, file.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry diskEntry
+		valid := json.Unmarshal(data, &entry) == nil && entry.Checksum == checksum(entry.Value)
+		if valid && (entry.ExpiresAt.IsZero() || now.Before(entry.ExpiresAt)) {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// path retorna o arquivo da entrada, nomeado pelo hash da chave
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+diskCacheSuffix)
+}
+
+// checksum retorna o SHA-256 do conteúdo em hexadecimal
+func checksum(value []byte) string {
+	sum := sha256.Sum256(value)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,212 @@
+package builder_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+// cacheFiles retorna os arquivos de entrada do diretório do cache
+func cacheFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.cache"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	return files
+}
+
+// entryFile retorna o arquivo da entrada da chave, nomeado pelo SHA-256 da chave
+func entryFile(dir, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".cache")
+}
+
+// rewriteEntry substitui o conteúdo do arquivo de uma entrada
+func rewriteEntry(t *testing.T, file string, entry map[string]interface{}) {
+	t.Helper()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestDiskCacheGetSet(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache, err := builder.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+	if cache.Dir() != dir {
+		t.Errorf("Dir = %s, esperado %s", cache.Dir(), dir)
+	}
+
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("Get de chave ausente = %t, %v", ok, err)
+	}
+
+	if err := cache.Set(ctx, "a", []byte("valor"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := cache.Set(ctx, "b", []byte("permanente"), 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	for key, want := range map[string]string{"a": "valor", "b": "permanente"} {
+		value, ok, err := cache.Get(ctx, key)
+		if err != nil || !ok || string(value) != want {
+			t.Errorf("Get(%s) = %q, %t, %v; esperado %q", key, value, ok, err, want)
+		}
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil || info.Mode().Perm() != 0o700 {
+		t.Errorf("permissão do diretório = %v, esperado 0700 (%v)", info.Mode().Perm(), err)
+	}
+	for _, file := range cacheFiles(t, dir) {
+		if info, err := os.Stat(file); err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("permissão de %s = %v, esperado 0600 (%v)", file, info.Mode().Perm(), err)
+		}
+	}
+
+	if err := cache.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := cache.Delete(ctx, "a"); err != nil {
+		t.Errorf("Delete de chave ausente: %v", err)
+	}
+	if _, ok, _ := cache.Get(ctx, "a"); ok {
+		t.Error("entrada removida ainda encontrada")
+	}
+}
+
+func TestDiskCacheExpiration(t *testing.T) {
+	ctx := context.Background()
+	cache, err := builder.NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	if err := cache.Set(ctx, "a", []byte("valor"), time.Millisecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok, err := cache.Get(ctx, "a"); ok || err != nil {
+		t.Errorf("Get de entrada expirada = %t, %v", ok, err)
+	}
+	if files := cacheFiles(t, cache.Dir()); len(files) != 0 {
+		t.Errorf("entrada expirada não removida: %v", files)
+	}
+}
+
+func TestDiskCacheCorruptEntry(t *testing.T) {
+	ctx := context.Background()
+	cache, err := builder.NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	value := []byte("valor")
+	sum := sha256.Sum256(value)
+	valid := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name  string
+		entry map[string]interface{}
+	}{
+		{name: "JSON inválido", entry: nil},
+		{name: "checksum divergente", entry: map[string]interface{}{"key": "a", "sha256": valid, "value": []byte("outro")}},
+		{name: "chave divergente", entry: map[string]interface{}{"key": "b", "sha256": valid, "value": value}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := cache.Set(ctx, "a", value, 0); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			file := entryFile(cache.Dir(), "a")
+			if tt.entry == nil {
+				os.WriteFile(file, []byte("{"), 0o600)
+			} else {
+				rewriteEntry(t, file, tt.entry)
+			}
+
+			_, ok, err := cache.Get(ctx, "a")
+			if ok || !errors.Is(err, builder.ErrCorruptCacheEntry) {
+				t.Errorf("Get = %t, %v; esperado ErrCorruptCacheEntry", ok, err)
+			}
+			if _, statErr := os.Stat(file); !os.IsNotExist(statErr) {
+				t.Error("entrada corrompida não removida")
+			}
+		})
+	}
+}
+
+func TestDiskCachePrune(t *testing.T) {
+	ctx := context.Background()
+	cache, err := builder.NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	cache.Set(ctx, "valid", []byte("1"), time.Hour)
+	cache.Set(ctx, "permanent", []byte("2"), 0)
+	cache.Set(ctx, "expired", []byte("3"), time.Millisecond)
+	cache.Set(ctx, "corrupt", []byte("4"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	rewriteEntry(t, entryFile(cache.Dir(), "corrupt"), map[string]interface{}{"key": "corrupt", "sha256": "00", "value": []byte("4")})
+	other := filepath.Join(cache.Dir(), "README")
+	os.WriteFile(other, []byte("não é entrada"), 0o600)
+
+	removed, err := cache.Prune(ctx)
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Prune removeu %d entradas, esperado 2", removed)
+	}
+	if files := cacheFiles(t, cache.Dir()); len(files) != 2 {
+		t.Errorf("restaram %d entradas, esperado 2", len(files))
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("arquivo que não é entrada removido: %v", err)
+	}
+}
+
+func TestDiskCacheAcrossBuilders(t *testing.T) {
+	dir := t.TempDir()
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/db/host": "localhost"})
+	opts := builder.BuildOptions{Prefixes: []string{"/app"}, StripPrefix: true}
+
+	for i := 0; i < 2; i++ {
+		cache, err := builder.NewDiskCache(dir)
+		if err != nil {
+			t.Fatalf("NewDiskCache: %v", err)
+		}
+		b := builder.New(fake)
+		b.SetCache(cache, time.Minute)
+		if _, err := b.BuildConfigFromPrefixes(context.Background(), opts); err != nil {
+			t.Fatalf("BuildConfigFromPrefixes: %v", err)
+		}
+	}
+
+	// A segunda execução, com outro builder, reaproveita os parâmetros gravados em disco
+	if calls := fake.Calls("GetParametersByPath"); calls != 1 {
+		t.Errorf("GetParametersByPath chamado %d vezes, esperado 1", calls)
+	}
+}
@@ -0,0 +1,157 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvFormat formato da saída como variáveis de ambiente
+type EnvFormat int
+
+const (
+	// EnvNone não gera variáveis de ambiente
+	EnvNone EnvFormat = iota
+	// EnvLambda gera o objeto Environment de uma função Lambda: {"Variables": {"NOME": "valor"}}
+	EnvLambda
+	// EnvECS gera o array "environment" de um container ECS: [{"name": "NOME", "value": "valor"}]
+	EnvECS
+)
+
+// lambdaEnvLimit tamanho máximo, em bytes, do conjunto de variáveis de uma função Lambda
+const lambdaEnvLimit = 4096
+
+// ecsEnvVariable item do array "environment" de uma task definition do ECS
+type ecsEnvVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// marshalEnv achata a árvore em variáveis de ambiente e as serializa no formato solicitado
+func (b *ConfigBuilder) marshalEnv(ctx context.Context, output map[string]interface{}, opts BuildOptions) ([]byte, error) {
+	variables, err := b.flattenEnv(output, opts.EnvPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.EnvOutput {
+	case EnvLambda:
+		size := 0
+		for name, value := range variables {
+			size += len(name) + len(value)
+		}
+		if size > lambdaEnvLimit {
+			b.warn(ctx, opts, "variáveis de ambiente somam %d bytes, acima do limite de %d bytes do Lambda", size, lambdaEnvLimit)
+		}
+		return marshalJSON(map[string]interface{}{"Variables": variables}, opts)
+	case EnvECS:
+		names := make([]string, 0, len(variables))
+		for name := range variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		environment := make([]ecsEnvVariable, 0, len(names))
+		for _, name := range names {
+			environment = append(environment, ecsEnvVariable{Name: name, Value: variables[name]})
+		}
+		return marshalJSON(environment, opts)
+	default:
+		return nil, fmt.Errorf("EnvOutput desconhecido: %d", opts.EnvOutput)
+	}
+}
+
+// flattenEnv converte a árvore em variáveis de ambiente: os caminhos são unidos por "_",
+// convertidos para maiúsculas e os caracteres fora de [A-Z0-9_] viram "_" (ex.:
+// database.port -> DATABASE_PORT, hosts[0] -> HOSTS_0). Caminhos que resultam no mesmo nome
+// geram erro.
+func (b *ConfigBuilder) flattenEnv(output map[string]interface{}, prefix string) (map[string]string, error) {
+	variables := make(map[string]string)
+	sources := make(map[string]string)
+	var errs []string
+
+	var walk func(value interface{}, path []string)
+	walk = func(value interface{}, path []string) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			// Chaves em ordem, para que a mensagem de nomes duplicados seja determinística
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(v[key], append(path, key))
+			}
+			return
+		case []interface{}:
+			for i, child := range v {
+				walk(child, append(path, strconv.Itoa(i)))
+			}
+			return
+		}
+
+		name := b.envName(prefix, path)
+		source := strings.Join(path, ".")
+		if previous, exists := sources[name]; exists {
+			errs = append(errs, fmt.Sprintf("%s e %s geram a variável %s", previous, source, name))
+			return
+		}
+		if name == "" || (name[0] >= '0' && name[0] <= '9') {
+			errs = append(errs, fmt.Sprintf("%s gera o nome de variável inválido %q (use EnvPrefix)", source, name))
+			return
+		}
+
+		sources[name] = source
+		variables[name] = b.envValue(value)
+	}
+	walk(output, nil)
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return nil, fmt.Errorf("erro ao gerar as variáveis de ambiente: %s", strings.Join(errs, "; "))
+	}
+	return variables, nil
+}
+
+// envName monta o nome da variável a partir do caminho
+func (b *ConfigBuilder) envName(prefix string, path []string) string {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	for i, segment := range path {
+		if i > 0 {
+			sb.WriteByte('_')
+		}
+		for _, r := range strings.ToUpper(segment) {
+			if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteByte('_')
+			}
+		}
+	}
+	return sb.String()
+}
+
+// envValue formata o valor da variável; nulo vira texto vazio
+func (b *ConfigBuilder) envValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
@@ -0,0 +1,130 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ExpirationInfo situação da política de expiração de um parâmetro
+type ExpirationInfo struct {
+	Name      string
+	ExpiresAt time.Time
+	Expired   bool
+}
+
+// expirationPolicy formato do texto de uma política "Expiration" do Parameter Store
+type expirationPolicy struct {
+	Type       string `json:"Type"`
+	Attributes struct {
+		Timestamp string `json:"Timestamp"`
+	} `json:"Attributes"`
+}
+
+// ExpirationReport lista os parâmetros dos prefixos com política de expiração vencida ou
+// que vence dentro de window, ordenados pela data de expiração. Prefixos informados como
+// ARN seguem as mesmas regras de BuildOptions.Prefixes sem ARNClient.
+func (b *ConfigBuilder) ExpirationReport(ctx context.Context, prefixes []string, window time.Duration) ([]ExpirationInfo, error) {
+	opts := BuildOptions{Prefixes: prefixes}
+	var report []ExpirationInfo
+	for _, spec := range b.resolvePrefixes(opts) {
+		prefixCtx, err := b.withPrefixClient(ctx, spec, opts)
+		if err != nil {
+			return nil, err
+		}
+		expirations, err := b.prefixExpirations(prefixCtx, spec.path, window)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, expirations...)
+	}
+
+	b.sortExpirations(report)
+	return report, nil
+}
+
+// prefixExpirations lista os parâmetros do prefixo com política de expiração vencida ou que
+// vence dentro de window, usando o cliente associado ao contexto
+func (b *ConfigBuilder) prefixExpirations(ctx context.Context, prefix string, window time.Duration) ([]ExpirationInfo, error) {
+	metadata, err := b.describeParameterMetadata(ctx, b.pathFilter(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar políticas do prefixo %s: %w", prefix, err)
+	}
+
+	var report []ExpirationInfo
+	now := time.Now()
+	for _, item := range metadata {
+		expiresAt, ok := b.expirationOf(item)
+		if !ok || expiresAt.After(now.Add(window)) {
+			continue
+		}
+		report = append(report, ExpirationInfo{
+			Name:      *item.Name,
+			ExpiresAt: expiresAt,
+			Expired:   !expiresAt.After(now),
+		})
+	}
+	return report, nil
+}
+
+// sortExpirations ordena o relatório pela data de expiração
+func (b *ConfigBuilder) sortExpirations(report []ExpirationInfo) {
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].ExpiresAt.Before(report[j].ExpiresAt)
+	})
+}
+
+// warnExpirations emite avisos para os parâmetros incluídos na construção que estão
+// expirados ou próximos de expirar, consultando cada prefixo com o seu cliente
+func (b *ConfigBuilder) warnExpirations(ctx context.Context, asm *assembly, opts BuildOptions) error {
+	var report []ExpirationInfo
+	for i, prefix := range asm.fetched {
+		expirations, err := b.prefixExpirations(asm.prefixContexts[i], prefix, opts.ExpirationWarning)
+		if err != nil {
+			return err
+		}
+		report = append(report, expirations...)
+	}
+	b.sortExpirations(report)
+
+	included := make(map[string]bool, len(asm.params))
+	for _, name := range b.parameterNames(asm.params) {
+		included[name] = true
+	}
+
+	for _, info := range report {
+		if !included[info.Name] {
+			continue
+		}
+		if info.Expired {
+			b.warn(ctx, opts, "parâmetro %s expirou em %s", info.Name, info.ExpiresAt.Format(time.RFC3339))
+		} else {
+			b.warn(ctx, opts, "parâmetro %s expira em %s", info.Name, info.ExpiresAt.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// expirationOf extrai a data da política de expiração do parâmetro, se houver
+func (b *ConfigBuilder) expirationOf(metadata types.ParameterMetadata) (time.Time, bool) {
+	for _, policy := range metadata.Policies {
+		if policy.PolicyType == nil || *policy.PolicyType != "Expiration" || policy.PolicyText == nil {
+			continue
+		}
+
+		var parsed expirationPolicy
+		if err := json.Unmarshal([]byte(*policy.PolicyText), &parsed); err != nil {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, parsed.Attributes.Timestamp)
+		if err != nil {
+			continue
+		}
+		return expiresAt, true
+	}
+	return time.Time{}, false
+}
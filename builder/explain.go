@@ -0,0 +1,203 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// describePageSize itens por página de DescribeParameters e GetParametersByPath sem MaxResults
+const describePageSize = 10
+
+// Plan descreve o que uma construção faria, sem buscar os valores dos parâmetros
+type Plan struct {
+	Prefixes       []PrefixPlan   // Prefixos na ordem de mescla
+	MergeStrategy  MergeStrategy  // Estratégia de mescla entre os prefixos
+	Steps          []string       // Etapas aplicadas após a mescla, em ordem
+	Output         string         // Formato de saída
+	EstimatedCalls map[string]int // Chamadas estimadas à API do SSM por operação, sem considerar o cache
+}
+
+// PrefixPlan plano de um prefixo
+type PrefixPlan struct {
+	Path        string
+	MountKey    string
+	StripPrefix bool
+	RawValues   bool
+	Recursive   bool
+	Filter      bool // Há um filtro de parâmetros
+	Transform   bool // Há uma transformação de valores
+	Parameters  int  // Parâmetros existentes sob o prefixo, antes de filtros e limites
+}
+
+// Explain valida as opções e relata o que a construção faria: prefixos, ordem de mescla,
+// filtros, etapas e chamadas estimadas à API. Apenas os metadados dos parâmetros são
+// consultados (DescribeParameters), nunca os valores.
+func (b *ConfigBuilder) Explain(ctx context.Context, opts BuildOptions) (*Plan, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	recursive := opts.Recursive == nil || *opts.Recursive
+	plan := &Plan{
+		MergeStrategy:  opts.MergeStrategy,
+		Steps:          b.planSteps(opts),
+		Output:         b.planOutput(opts),
+		EstimatedCalls: make(map[string]int),
+	}
+
+	for _, spec := range b.resolvePrefixes(opts) {
+		prefixCtx, err := b.withPrefixClient(ctx, spec, opts)
+		if err != nil {
+			return nil, b.annotate(ctx, err)
+		}
+		count, err := b.countParameters(prefixCtx, spec.path, recursive)
+		if err != nil {
+			return nil, b.annotate(ctx, fmt.Errorf("erro ao consultar os parâmetros do prefixo %s: %w", spec.path, err))
+		}
+
+		if opts.MaxParameters > 0 && count > opts.MaxParameters {
+			count = opts.MaxParameters
+		}
+		plan.Prefixes = append(plan.Prefixes, PrefixPlan{
+			Path:        spec.path,
+			MountKey:    spec.key,
+			StripPrefix: spec.stripPrefix,
+			RawValues:   spec.rawValues,
+			Recursive:   recursive,
+			Filter:      spec.filter != nil,
+			Transform:   spec.transform != nil,
+			Parameters:  count,
+		})
+
+		plan.EstimatedCalls["GetParametersByPath"] += pages(count)
+		if opts.YAMLComments || opts.JSONCOutput {
+			plan.EstimatedCalls["DescribeParameters"] += pages(count)
+		}
+		if opts.ExpirationWarning > 0 {
+			plan.EstimatedCalls["DescribeParameters"] += pages(count)
+		}
+		if opts.TagsMetadata {
+			plan.EstimatedCalls["ListTagsForResource"] += count
+		}
+	}
+
+	return plan, nil
+}
+
+// String formata o plano para leitura em logs e revisões de CI
+func (p *Plan) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "saída: %s\n", p.Output)
+	fmt.Fprintf(&sb, "estratégia de mescla: %s\n", p.MergeStrategy)
+	sb.WriteString("prefixos (ordem de mescla):\n")
+	for i, prefix := range p.Prefixes {
+		fmt.Fprintf(&sb, "  %d. %s (%d parâmetros", i+1, prefix.Path, prefix.Parameters)
+		if prefix.MountKey != "" {
+			fmt.Fprintf(&sb, ", montado em %q", prefix.MountKey)
+		}
+		if !prefix.Recursive {
+			sb.WriteString(", não recursivo")
+		}
+		if prefix.StripPrefix {
+			sb.WriteString(", sem prefixo")
+		}
+		if prefix.RawValues {
+			sb.WriteString(", valores brutos")
+		}
+		if prefix.Filter {
+			sb.WriteString(", com filtro")
+		}
+		if prefix.Transform {
+			sb.WriteString(", com transformação")
+		}
+		sb.WriteString(")\n")
+	}
+	if len(p.Steps) > 0 {
+		fmt.Fprintf(&sb, "etapas: %s\n", strings.Join(p.Steps, ", "))
+	}
+	sb.WriteString("chamadas estimadas:")
+	for _, operation := range []string{"GetParametersByPath", "DescribeParameters", "ListTagsForResource"} {
+		if calls := p.EstimatedCalls[operation]; calls > 0 {
+			fmt.Fprintf(&sb, " %s=%d", operation, calls)
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// countParameters conta os parâmetros sob o path pelos metadados, sem buscar os valores
+func (b *ConfigBuilder) countParameters(ctx context.Context, path string, recursive bool) (int, error) {
+	filters := b.pathFilter(path)
+	if !recursive {
+		filters[0].Option = aws.String("OneLevel")
+	}
+
+	metadata, err := b.describeParameterMetadata(ctx, filters)
+	if err != nil {
+		return 0, err
+	}
+	return len(metadata), nil
+}
+
+// planSteps lista as etapas aplicadas durante e após a mescla, na ordem de assemble e render
+func (b *ConfigBuilder) planSteps(opts BuildOptions) []string {
+	var steps []string
+	add := func(enabled bool, step string) {
+		if enabled {
+			steps = append(steps, step)
+		}
+	}
+
+	add(len(opts.Decryptors) > 0, fmt.Sprintf("decifrar valores (%d decifradores)", len(opts.Decryptors)))
+	add(opts.ExpandStringLists, "expandir listas")
+	add(opts.MaxDepth > 0, fmt.Sprintf("limitar profundidade a %d", opts.MaxDepth))
+	add(opts.KeySanitization != KeySanitizeNone, "sanitizar chaves")
+	add(opts.CaseInsensitiveKeys, "unificar maiúsculas/minúsculas das chaves")
+	add(len(opts.KeyRemaps) > 0, fmt.Sprintf("remapear chaves (%d regras)", len(opts.KeyRemaps)))
+	add(opts.PatchPath != "", fmt.Sprintf("aplicar JSON Patch de %q", opts.PatchPath))
+	add(len(opts.Conditions) > 0, fmt.Sprintf("avaliar condições de inclusão (%d padrões)", len(opts.Conditions)))
+	add(len(opts.TypeSchema) > 0, fmt.Sprintf("converter tipos (%d padrões)", len(opts.TypeSchema)))
+	add(len(opts.Validators) > 0, fmt.Sprintf("validar (%d validadores)", len(opts.Validators)))
+	add(!opts.YAMLRules && opts.SortByDependencies, "ordenar tipos por dependência")
+	add(opts.TagsMetadata, "anexar tags em "+MetadataKey)
+	add(opts.BuildMetadata, "anexar proveniência em "+BuildKey)
+	add(opts.ExpirationWarning > 0, "avisar expirações")
+	add(opts.RootKey != "", fmt.Sprintf("envolver em %q", opts.RootKey))
+	add(opts.Gzip, "comprimir em gzip")
+	return steps
+}
+
+// planOutput descreve o formato de saída selecionado pelas opções
+func (b *ConfigBuilder) planOutput(opts BuildOptions) string {
+	switch {
+	case opts.YAMLRules && opts.YAMLMultiDocument:
+		return "YAML (um documento por chave)"
+	case opts.YAMLRules:
+		return "YAML"
+	case opts.NDJSONOutput:
+		return "NDJSON"
+	case opts.JSONCOutput:
+		return "JSONC (com comentários)"
+	case opts.GraphQLOutput:
+		return "JSON (introspecção GraphQL)"
+	case opts.EnvOutput == EnvLambda:
+		return "variáveis de ambiente (Lambda)"
+	case opts.EnvOutput == EnvECS:
+		return "variáveis de ambiente (ECS)"
+	case opts.JSONOutput:
+		return "JSON indentado"
+	default:
+		return "JSON compacto"
+	}
+}
+
+// pages número de páginas necessárias para n itens, com ao menos uma chamada
+func pages(n int) int {
+	if n == 0 {
+		return 1
+	}
+	return (n + describePageSize - 1) / describePageSize
+}
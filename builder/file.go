@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileWriteOptions opções de gravação da configuração em arquivo
+type FileWriteOptions struct {
+	BuildOptions
+	Perm os.FileMode // Permissões do arquivo final (padrão: 0644)
+}
+
+// BuildToFile constrói a configuração e grava o documento de forma atômica: o conteúdo é
+// escrito em um arquivo temporário no mesmo diretório e renomeado para path, de modo que
+// leitores nunca observem um arquivo parcialmente escrito.
+// No modo BestEffort, o documento parcial é gravado e o *PartialError é retornado.
+func (b *ConfigBuilder) BuildToFile(ctx context.Context, path string, opts FileWriteOptions) error {
+	data, buildErr := b.BuildConfigFromPrefixes(ctx, opts.BuildOptions)
+	var partial *PartialError
+	if buildErr != nil && !errors.As(buildErr, &partial) {
+		return buildErr
+	}
+
+	perm := opts.Perm
+	if perm == 0 {
+		perm = 0o644
+	}
+
+	if err := writeFileAtomic(path, data, perm); err != nil {
+		return err
+	}
+	return buildErr
+}
+
+// writeFileAtomic grava data em path via arquivo temporário e rename
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo temporário para %s: %w", path, err)
+	}
+	tmpName := tmp.Name()
+
+	cleanup := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(fmt.Errorf("erro ao gravar %s: %w", tmpName, err))
+	}
+	if err := tmp.Sync(); err != nil {
+		return cleanup(fmt.Errorf("erro ao sincronizar %s: %w", tmpName, err))
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return cleanup(fmt.Errorf("erro ao definir permissões de %s: %w", tmpName, err))
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("erro ao fechar %s: %w", tmpName, err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("erro ao mover %s para %s: %w", tmpName, path, err)
+	}
+	return nil
+}
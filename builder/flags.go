@@ -0,0 +1,118 @@
+package builder
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// variantKey atributo com o nome da variante nos flags multivariantes do AppConfig
+const variantKey = "_variant"
+
+// Flags acesso tipado a um perfil de feature flags no formato avaliado do AppConfig
+// (AWS.AppConfig.FeatureFlags), em que cada flag é um objeto com "enabled", os atributos
+// e, nos flags multivariantes, "_variant":
+//
+//	{"checkout": {"enabled": true, "limit": 10, "_variant": "beta"}}
+//
+// Flags definidos apenas como booleano ("checkout": true) também são aceitos. O perfil pode
+// vir de qualquer fonte mesclada no documento (Parameter Store, arquivo ou Source).
+type Flags struct {
+	config *Config
+}
+
+// Flag estado de um feature flag
+type Flag struct {
+	Name       string
+	Enabled    bool
+	Variant    string                 // Variante avaliada, nos flags multivariantes
+	Attributes map[string]interface{} // Atributos do flag, exceto "enabled" e "_variant"
+}
+
+// Flags retorna os feature flags da seção do caminho (ex.: "features"); um caminho vazio
+// usa a raiz do documento
+func (c *Config) Flags(path string) *Flags {
+	return &Flags{config: c.Sub(path)}
+}
+
+// IsEnabled indica se o flag existe e está habilitado
+func (f *Flags) IsEnabled(name string) bool {
+	flag, ok := f.Flag(name)
+	return ok && flag.Enabled
+}
+
+// Variant retorna a variante avaliada do flag habilitado, se houver
+func (f *Flags) Variant(name string) (string, bool) {
+	flag, ok := f.Flag(name)
+	if !ok || !flag.Enabled || flag.Variant == "" {
+		return "", false
+	}
+	return flag.Variant, true
+}
+
+// Attribute retorna um atributo do flag habilitado como um Config, para leitura tipada com
+// os métodos Get (ex.: flags.Attribute("checkout").GetInt("limit"))
+func (f *Flags) Attribute(name string) *Config {
+	flag, ok := f.Flag(name)
+	if !ok || !flag.Enabled {
+		return NewConfig(nil)
+	}
+	return &Config{data: flag.Attributes, caseInsensitive: f.config.caseInsensitive}
+}
+
+// Flag retorna o estado do flag; false quando ele não existe ou não tem um formato reconhecido
+func (f *Flags) Flag(name string) (Flag, bool) {
+	_, value, ok := f.config.lookup(f.config.data, name)
+	if !ok {
+		return Flag{}, false
+	}
+
+	flag := Flag{Name: name, Attributes: make(map[string]interface{})}
+	switch v := value.(type) {
+	case bool:
+		flag.Enabled = v
+	case string:
+		enabled, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return Flag{}, false
+		}
+		flag.Enabled = enabled
+	case map[string]interface{}:
+		for key, attribute := range v {
+			switch key {
+			case "enabled":
+				flag.Enabled, _ = attribute.(bool)
+			case variantKey:
+				flag.Variant, _ = attribute.(string)
+			default:
+				flag.Attributes[key] = attribute
+			}
+		}
+	default:
+		return Flag{}, false
+	}
+	return flag, true
+}
+
+// Names retorna os nomes dos flags do perfil, ordenados
+func (f *Flags) Names() []string {
+	names := make([]string, 0, len(f.config.data))
+	for name := range f.config.data {
+		if _, ok := f.Flag(name); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Enabled retorna os nomes dos flags habilitados, ordenados
+func (f *Flags) Enabled() []string {
+	var names []string
+	for _, name := range f.Names() {
+		if f.IsEnabled(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
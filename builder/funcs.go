@@ -0,0 +1,183 @@
+package builder
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateFuncs retorna funções auxiliares no estilo do sprig para templates text/template
+// que geram arquivos de configuração a partir de Config.Map() (ex.:
+// template.New("app").Funcs(builder.TemplateFuncs())). Os nomes e a ordem dos argumentos
+// seguem o sprig, permitindo o uso em pipelines como {{ .db.port | default 5432 }}.
+//
+//   - default, empty, coalesce e required: valores padrão e obrigatórios
+//   - toJson, toPrettyJson, toYaml: serialização de subárvores
+//   - b64enc, b64dec: codificação em base64
+//   - indent, nindent: indentação de blocos (ex.: {{ .rules | toYaml | nindent 4 }})
+//   - quote, squote, upper, lower, trim, trimPrefix, trimSuffix, replace, join, split
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"default":  templateDefault,
+		"empty":    isEmptyValue,
+		"coalesce": templateCoalesce,
+		"required": templateRequired,
+
+		"toJson":       templateToJSON,
+		"toPrettyJson": templateToPrettyJSON,
+		"toYaml":       templateToYAML,
+
+		"b64enc": func(value interface{}) string {
+			return base64.StdEncoding.EncodeToString([]byte(toText(value)))
+		},
+		"b64dec": func(value string) (string, error) {
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return "", fmt.Errorf("b64dec: %w", err)
+			}
+			return string(decoded), nil
+		},
+
+		"indent": templateIndent,
+		"nindent": func(spaces int, value string) string {
+			return "\n" + templateIndent(spaces, value)
+		},
+
+		"quote": func(values ...interface{}) string {
+			return joinQuoted(values, func(text string) string { return fmt.Sprintf("%q", text) })
+		},
+		"squote": func(values ...interface{}) string {
+			return joinQuoted(values, func(text string) string { return "'" + text + "'" })
+		},
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, value string) string { return strings.TrimPrefix(value, prefix) },
+		"trimSuffix": func(suffix, value string) string { return strings.TrimSuffix(value, suffix) },
+		"replace":    func(old, replacement, value string) string { return strings.ReplaceAll(value, old, replacement) },
+		"join":       templateJoin,
+		"split":      func(sep, value string) []string { return strings.Split(value, sep) },
+	}
+}
+
+// templateDefault retorna fallback quando o valor está ausente ou vazio
+func templateDefault(fallback interface{}, given ...interface{}) interface{} {
+	if len(given) == 0 || isEmptyValue(given[0]) {
+		return fallback
+	}
+	return given[0]
+}
+
+// templateCoalesce retorna o primeiro valor não vazio
+func templateCoalesce(values ...interface{}) interface{} {
+	for _, value := range values {
+		if !isEmptyValue(value) {
+			return value
+		}
+	}
+	return nil
+}
+
+// templateRequired interrompe a execução do template com a mensagem quando o valor está
+// ausente ou é texto vazio
+func templateRequired(message string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, errors.New(message)
+	}
+	if text, ok := value.(string); ok && text == "" {
+		return nil, errors.New(message)
+	}
+	return value, nil
+}
+
+// isEmptyValue indica se o valor é nulo ou o valor zero do seu tipo (texto, lista e mapa
+// vazios, zero e false)
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// templateToJSON serializa o valor em JSON compacto
+func templateToJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("toJson: %w", err)
+	}
+	return string(data), nil
+}
+
+// templateToPrettyJSON serializa o valor em JSON indentado com dois espaços
+func templateToPrettyJSON(value interface{}) (string, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("toPrettyJson: %w", err)
+	}
+	return string(data), nil
+}
+
+// templateToYAML serializa o valor em YAML, sem a quebra de linha final
+func templateToYAML(value interface{}) (string, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("toYaml: %w", err)
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// templateIndent indenta todas as linhas do texto com o número de espaços informado
+func templateIndent(spaces int, value string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(value, "\n", "\n"+pad)
+}
+
+// templateJoin une os elementos de uma lista (de qualquer tipo) com o separador
+func templateJoin(sep string, list interface{}) string {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return toText(list)
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = toText(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+// joinQuoted cita cada valor não nulo e os une com espaços
+func joinQuoted(values []interface{}, quote func(text string) string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			quoted = append(quoted, quote(toText(value)))
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// toText formata o valor como texto, sem notação científica para números
+func toText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,262 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// graphqlScalars escalares nativos do GraphQL, incluídos automaticamente na introspecção
+var graphqlScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+// introspectionSchema converte o schema montado (nó "types", como o usado por
+// SortByDependencies) no formato de resultado da consulta de introspecção do GraphQL
+// ({"__schema": {...}}), consumido por ferramentas como GraphiQL e geradores de código.
+//
+// Cada tipo tem name, kind (padrão OBJECT), description e, conforme o kind, fields,
+// values (ENUM), interfaces ou possibleTypes (UNION). Cada campo tem name, description,
+// deprecated (motivo), args e type em notação SDL (ex.: "[User!]!"); sem type, o campo
+// ofType é usado como nome do tipo. queryType, mutationType e subscriptionType usam os
+// tipos Query, Mutation e Subscription quando não informados.
+func (b *ConfigBuilder) introspectionSchema(schema map[string]interface{}) (map[string]interface{}, error) {
+	declared, ok := schema["types"].([]interface{})
+	if !ok {
+		return nil, errors.New("'types' não encontrado ou não é uma lista")
+	}
+
+	// Primeiro registramos o kind de cada tipo, usado nas referências entre tipos
+	kinds := make(map[string]string, len(declared)+len(graphqlScalars))
+	for _, name := range graphqlScalars {
+		kinds[name] = "SCALAR"
+	}
+	for i, t := range declared {
+		typeObj, ok := t.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("tipo %d não é um objeto", i)
+		}
+		name, _ := typeObj["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("tipo %d sem name", i)
+		}
+		kinds[name] = strings.ToUpper(stringOr(typeObj["kind"], "OBJECT"))
+	}
+
+	var errs []error
+	types := make([]interface{}, 0, len(kinds))
+	for _, t := range declared {
+		typeObj := t.(map[string]interface{})
+		converted, err := b.introspectionType(typeObj, kinds)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tipo %s: %w", typeObj["name"], err))
+			continue
+		}
+		types = append(types, converted)
+	}
+	for _, name := range graphqlScalars {
+		if !declaresType(declared, name) {
+			types = append(types, introspectionScalar(name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	result := map[string]interface{}{
+		"types":      types,
+		"directives": []interface{}{},
+	}
+	for key, fallback := range map[string]string{"queryType": "Query", "mutationType": "Mutation", "subscriptionType": "Subscription"} {
+		name := stringOr(schema[key], fallback)
+		if _, exists := kinds[name]; exists {
+			result[key] = map[string]interface{}{"name": name}
+		} else if schema[key] != nil {
+			return nil, fmt.Errorf("%s referencia o tipo inexistente %s", key, name)
+		} else {
+			result[key] = nil
+		}
+	}
+
+	return map[string]interface{}{"__schema": result}, nil
+}
+
+// introspectionType converte um tipo declarado em um __Type
+func (b *ConfigBuilder) introspectionType(typeObj map[string]interface{}, kinds map[string]string) (map[string]interface{}, error) {
+	name := typeObj["name"].(string)
+	kind := kinds[name]
+	result := map[string]interface{}{
+		"kind":          kind,
+		"name":          name,
+		"description":   typeObj["description"],
+		"fields":        nil,
+		"inputFields":   nil,
+		"interfaces":    nil,
+		"enumValues":    nil,
+		"possibleTypes": nil,
+	}
+
+	switch kind {
+	case "OBJECT", "INTERFACE":
+		fields, err := b.introspectionFields(typeObj["fields"], kinds, false)
+		if err != nil {
+			return nil, err
+		}
+		result["fields"] = fields
+		result["interfaces"] = introspectionNamedRefs(typeObj["interfaces"], kinds)
+		if kind == "INTERFACE" {
+			result["possibleTypes"] = introspectionNamedRefs(typeObj["possibleTypes"], kinds)
+		}
+	case "INPUT_OBJECT":
+		fields, err := b.introspectionFields(typeObj["fields"], kinds, true)
+		if err != nil {
+			return nil, err
+		}
+		result["inputFields"] = fields
+	case "ENUM":
+		values, _ := typeObj["values"].([]interface{})
+		enumValues := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			value := map[string]interface{}{"description": nil, "isDeprecated": false, "deprecationReason": nil}
+			switch ev := v.(type) {
+			case string:
+				value["name"] = ev
+			case map[string]interface{}:
+				value["name"] = ev["name"]
+				value["description"] = ev["description"]
+				setDeprecation(value, ev)
+			default:
+				return nil, fmt.Errorf("valor de enum inválido: %v", v)
+			}
+			enumValues = append(enumValues, value)
+		}
+		result["enumValues"] = enumValues
+	case "UNION":
+		result["possibleTypes"] = introspectionNamedRefs(typeObj["possibleTypes"], kinds)
+	case "SCALAR":
+	default:
+		return nil, fmt.Errorf("kind desconhecido %q", kind)
+	}
+
+	return result, nil
+}
+
+// introspectionFields converte os campos (ou argumentos) declarados em __Field ou __InputValue
+func (b *ConfigBuilder) introspectionFields(value interface{}, kinds map[string]string, input bool) ([]interface{}, error) {
+	declared, _ := value.([]interface{})
+	fields := make([]interface{}, 0, len(declared))
+	for i, f := range declared {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("campo %d não é um objeto", i)
+		}
+
+		ref := stringOr(field["type"], stringOr(field["ofType"], ""))
+		if ref == "" {
+			return nil, fmt.Errorf("campo %v sem type ou ofType", field["name"])
+		}
+		typeRef, err := introspectionTypeRef(ref, kinds)
+		if err != nil {
+			return nil, fmt.Errorf("campo %v: %w", field["name"], err)
+		}
+
+		converted := map[string]interface{}{
+			"name":        field["name"],
+			"description": field["description"],
+			"type":        typeRef,
+		}
+		if input {
+			converted["defaultValue"] = field["defaultValue"]
+		} else {
+			args, err := b.introspectionFields(field["args"], kinds, true)
+			if err != nil {
+				return nil, fmt.Errorf("argumentos do campo %v: %w", field["name"], err)
+			}
+			converted["args"] = args
+			setDeprecation(converted, field)
+		}
+		fields = append(fields, converted)
+	}
+	return fields, nil
+}
+
+// introspectionTypeRef converte uma referência em notação SDL (ex.: "[User!]!") em __Type
+func introspectionTypeRef(ref string, kinds map[string]string) (map[string]interface{}, error) {
+	ref = strings.TrimSpace(ref)
+	switch {
+	case strings.HasSuffix(ref, "!"):
+		ofType, err := introspectionTypeRef(strings.TrimSuffix(ref, "!"), kinds)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": ofType}, nil
+	case strings.HasPrefix(ref, "[") && strings.HasSuffix(ref, "]"):
+		ofType, err := introspectionTypeRef(ref[1:len(ref)-1], kinds)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"kind": "LIST", "name": nil, "ofType": ofType}, nil
+	}
+
+	kind, ok := kinds[ref]
+	if !ok {
+		return nil, fmt.Errorf("tipo desconhecido %q", ref)
+	}
+	return map[string]interface{}{"kind": kind, "name": ref, "ofType": nil}, nil
+}
+
+// introspectionNamedRefs converte uma lista de nomes de tipos em referências __Type
+func introspectionNamedRefs(value interface{}, kinds map[string]string) []interface{} {
+	names, _ := value.([]interface{})
+	refs := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		if s, ok := name.(string); ok {
+			refs = append(refs, map[string]interface{}{"kind": kinds[s], "name": s, "ofType": nil})
+		}
+	}
+	return refs
+}
+
+// introspectionScalar retorna o __Type de um escalar nativo
+func introspectionScalar(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"kind":          "SCALAR",
+		"name":          name,
+		"description":   nil,
+		"fields":        nil,
+		"inputFields":   nil,
+		"interfaces":    nil,
+		"enumValues":    nil,
+		"possibleTypes": nil,
+	}
+}
+
+// setDeprecation preenche isDeprecated e deprecationReason a partir do campo "deprecated"
+func setDeprecation(target, declared map[string]interface{}) {
+	reason, deprecated := declared["deprecated"].(string)
+	if flag, ok := declared["deprecated"].(bool); ok && flag {
+		reason, deprecated = "No longer supported", true
+	}
+	target["isDeprecated"] = deprecated
+	if deprecated {
+		target["deprecationReason"] = reason
+	} else {
+		target["deprecationReason"] = nil
+	}
+}
+
+// declaresType indica se o schema já declara o tipo
+func declaresType(declared []interface{}, name string) bool {
+	for _, t := range declared {
+		if typeObj, ok := t.(map[string]interface{}); ok && typeObj["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// stringOr retorna o valor como string ou fallback quando não é uma string não vazia
+func stringOr(value interface{}, fallback string) string {
+	if s, ok := value.(string); ok && s != "" {
+		return s
+	}
+	return fallback
+}
@@ -0,0 +1,272 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// handlerFormat representação servida pelo Handler
+type handlerFormat struct {
+	format      Format   // Formato de Convert
+	contentType string   // Content-Type da resposta
+	mediaTypes  []string // Tipos aceitos no cabeçalho Accept
+}
+
+// handlerFormats formatos oferecidos pelo Handler, em ordem de preferência
+var handlerFormats = []handlerFormat{
+	{format: FormatJSON, contentType: "application/json", mediaTypes: []string{"application/json"}},
+	{format: FormatYAML, contentType: "application/yaml", mediaTypes: []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"}},
+}
+
+// skippedHeader cabeçalho com os prefixos ignorados de uma configuração parcial
+const skippedHeader = "X-Config-Skipped-Prefixes"
+
+// configHandler implementação de http.Handler retornada por Handler
+type configHandler struct {
+	builder  *ConfigBuilder
+	opts     BuildOptions
+	format   Format                   // Formato gerado pelas opções
+	prefixes map[string]handlerPrefix // Prefixos servidos, pelo caminho
+}
+
+// handlerPrefix opções de um prefixo servido e o resumo delas usado no ETag
+type handlerPrefix struct {
+	opts        BuildOptions
+	fingerprint string
+}
+
+// Handler retorna um http.Handler que serve a configuração de cada prefixo das opções no
+// caminho do próprio prefixo (ex.: GET /app/prod), para que sidecars e outros serviços
+// obtenham a configuração de um único processo com acesso ao SSM. Use http.StripPrefix
+// para montá-lo sob outro caminho.
+//
+// O formato é negociado pelo cabeçalho Accept entre application/json (padrão) e
+// application/yaml: o documento é gerado com as opções informadas e convertido com Convert
+// quando o formato pedido é o outro. O ETag combina o resumo das opções com o hash dos
+// parâmetros (ver BuildIfModified): com If-None-Match correspondente, a resposta é 304 sem
+// montar o documento, e a alteração das opções (ex.: TagsMetadata) invalida os ETags já
+// emitidos. Com BestEffort, a falha na busca do prefixo não impede a resposta: o documento
+// montado sem ele é servido com status 200 e o prefixo no cabeçalho
+// X-Config-Skipped-Prefixes. Os demais formatos de saída, YAMLMultiDocument e Gzip não são
+// suportados.
+func (b *ConfigBuilder) Handler(opts BuildOptions) (http.Handler, error) {
+	if opts.NDJSONOutput || opts.EnvOutput != EnvNone || opts.JSONCOutput || opts.GraphQLOutput || opts.YAMLMultiDocument || opts.Gzip {
+		return nil, errors.New("Handler serve apenas JSON e YAML: NDJSONOutput, EnvOutput, JSONCOutput, GraphQLOutput, YAMLMultiDocument e Gzip não são suportados")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	h := &configHandler{builder: b, opts: opts, format: FormatJSON, prefixes: make(map[string]handlerPrefix)}
+	if opts.YAMLRules {
+		h.format = FormatYAML
+	}
+	add := func(path string, prefixOpts BuildOptions) error {
+		if _, exists := h.prefixes[path]; exists {
+			return fmt.Errorf("prefixo %s duplicado", path)
+		}
+		sum := sha256.Sum256([]byte(b.optionsFingerprint(prefixOpts)))
+		h.prefixes[path] = handlerPrefix{opts: prefixOpts, fingerprint: hex.EncodeToString(sum[:8])}
+		return nil
+	}
+
+	for _, prefix := range opts.Prefixes {
+		prefixOpts := opts
+		prefixOpts.Prefixes = []string{prefix}
+		prefixOpts.PrefixSpecs = nil
+		prefixOpts.MountKeys = nil
+		if key, ok := opts.MountKeys[prefix]; ok {
+			prefixOpts.MountKeys = map[string]string{prefix: key}
+		}
+		path, _ := normalizePrefix(prefix)
+		if err := add(path, prefixOpts); err != nil {
+			return nil, err
+		}
+	}
+	for _, spec := range opts.PrefixSpecs {
+		prefixOpts := opts
+		prefixOpts.Prefixes = nil
+		prefixOpts.PrefixSpecs = []PrefixSpec{spec}
+		prefixOpts.MountKeys = nil
+		path, _ := normalizePrefix(spec.Path)
+		if err := add(path, prefixOpts); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// ServeHTTP implementa http.Handler
+func (h *configHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := r.URL.Path
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	prefix, ok := h.prefixes[path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Vary", "Accept")
+	format, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, "formato não suportado: use application/json ou application/yaml", http.StatusNotAcceptable)
+		return
+	}
+
+	tagPrefix := string(format.format) + "-" + prefix.fingerprint + "-"
+	wildcard, hashes := matchingHashes(r.Header.Get("If-None-Match"), tagPrefix)
+	previousHash := ""
+	if len(hashes) > 0 {
+		previousHash = hashes[0]
+	}
+
+	ctx := r.Context()
+	data, hash, err := h.builder.BuildIfModified(ctx, prefix.opts, previousHash)
+	var partial *PartialError
+	if errors.As(err, &partial) {
+		h.builder.warn(ctx, h.opts, "configuração parcial do prefixo %s: %v", path, err)
+		err = nil
+	}
+	if err == nil && format.format != h.format {
+		data, err = Convert(data, h.format, format.format)
+	}
+	if err != nil && !errors.Is(err, ErrNotModified) {
+		h.builder.warn(ctx, h.opts, "erro ao servir a configuração do prefixo %s: %v", path, err)
+		http.Error(w, "erro ao construir a configuração", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("ETag", strconv.Quote(tagPrefix+hash))
+	w.Header().Set("Cache-Control", "no-cache")
+	if err != nil || wildcard || slices.Contains(hashes, hash) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if partial != nil {
+		skipped := make([]string, 0, len(partial.Skipped))
+		for _, prefixErr := range partial.Skipped {
+			skipped = append(skipped, prefixErr.Prefix)
+		}
+		w.Header().Set(skippedHeader, strings.Join(skipped, ", "))
+	}
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(data)
+	}
+}
+
+// acceptRange faixa de tipos do cabeçalho Accept
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// negotiate escolhe o formato de maior qualidade no cabeçalho Accept. Cada formato recebe
+// a qualidade da faixa mais específica que o inclui; empates favorecem JSON. Sem Accept,
+// JSON é escolhido.
+func negotiate(accept string) (handlerFormat, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return handlerFormats[0], true
+	}
+	ranges := parseAccept(accept)
+
+	best, bestQuality := -1, 0.0
+	for i, format := range handlerFormats {
+		quality := formatQuality(format, ranges)
+		if quality > bestQuality {
+			best, bestQuality = i, quality
+		}
+	}
+	if best < 0 {
+		return handlerFormat{}, false
+	}
+	return handlerFormats[best], true
+}
+
+// parseAccept lê as faixas do cabeçalho Accept com seus parâmetros q
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.ToLower(strings.TrimSpace(name)) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+	return ranges
+}
+
+// formatQuality retorna a qualidade da faixa mais específica que inclui o formato
+func formatQuality(format handlerFormat, ranges []acceptRange) float64 {
+	quality, specificity := 0.0, -1
+	for _, r := range ranges {
+		for _, mediaType := range format.mediaTypes {
+			kind, _, _ := strings.Cut(mediaType, "/")
+			var level int
+			switch r.mediaType {
+			case mediaType:
+				level = 2
+			case kind + "/*":
+				level = 1
+			case "*/*":
+				level = 0
+			default:
+				continue
+			}
+			if level > specificity || (level == specificity && r.quality > quality) {
+				quality, specificity = r.quality, level
+			}
+		}
+	}
+	return quality
+}
+
+// matchingHashes retorna se If-None-Match contém "*" e os hashes dos ETags iniciados por
+// tagPrefix (formato e resumo das opções)
+func matchingHashes(ifNoneMatch string, tagPrefix string) (bool, []string) {
+	var wildcard bool
+	var hashes []string
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" {
+			wildcard = true
+			continue
+		}
+		value, err := strconv.Unquote(tag)
+		if err != nil {
+			continue
+		}
+		if hash, ok := strings.CutPrefix(value, tagPrefix); ok && hash != "" {
+			hashes = append(hashes, hash)
+		}
+	}
+	return wildcard, hashes
+}
@@ -0,0 +1,181 @@
+package builder_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+// newHandler cria o Handler do prefixo /app/prod sobre um Parameter Store simulado
+func newHandler(t *testing.T, fake *ssmtest.Client, opts builder.BuildOptions) http.Handler {
+	t.Helper()
+	opts.Prefixes = []string{"/app/prod"}
+	opts.StripPrefix = true
+	handler, err := builder.New(fake).Handler(opts)
+	if err != nil {
+		t.Fatalf("Handler: %v", err)
+	}
+	return handler
+}
+
+// serve executa uma requisição no handler com os cabeçalhos informados
+func serve(handler http.Handler, method, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// seededFake cria o Parameter Store simulado com os parâmetros de /app/prod
+func seededFake() *ssmtest.Client {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/prod/db/host": "localhost",
+		"/app/prod/debug":   "true",
+	})
+	return fake
+}
+
+func TestHandlerResponses(t *testing.T) {
+	handler := newHandler(t, seededFake(), builder.BuildOptions{})
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{name: "JSON por padrão", method: http.MethodGet, path: "/app/prod", status: http.StatusOK, contentType: "application/json", body: `"host":"localhost"`},
+		{name: "barra final", method: http.MethodGet, path: "/app/prod/", status: http.StatusOK, contentType: "application/json", body: `"host":"localhost"`},
+		{name: "YAML pelo Accept", method: http.MethodGet, path: "/app/prod", accept: "application/yaml", status: http.StatusOK, contentType: "application/yaml", body: "host: localhost"},
+		{name: "qualidade maior para YAML", method: http.MethodGet, path: "/app/prod", accept: "application/json;q=0.5, text/yaml;q=0.9", status: http.StatusOK, contentType: "application/yaml"},
+		{name: "faixa text/*", method: http.MethodGet, path: "/app/prod", accept: "text/*", status: http.StatusOK, contentType: "application/yaml"},
+		{name: "empate favorece JSON", method: http.MethodGet, path: "/app/prod", accept: "*/*", status: http.StatusOK, contentType: "application/json"},
+		{name: "faixa específica com q=0", method: http.MethodGet, path: "/app/prod", accept: "*/*, application/json;q=0", status: http.StatusOK, contentType: "application/yaml"},
+		{name: "formato não aceito", method: http.MethodGet, path: "/app/prod", accept: "text/html", status: http.StatusNotAcceptable},
+		{name: "HEAD sem corpo", method: http.MethodHead, path: "/app/prod", status: http.StatusOK, contentType: "application/json"},
+		{name: "método não permitido", method: http.MethodPost, path: "/app/prod", status: http.StatusMethodNotAllowed},
+		{name: "prefixo desconhecido", method: http.MethodGet, path: "/app/dev", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(handler, tt.method, tt.path, map[string]string{"Accept": tt.accept})
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, esperado %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.contentType != "" && rec.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %q, esperado %q", rec.Header().Get("Content-Type"), tt.contentType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("corpo = %s, esperado conter %s", rec.Body, tt.body)
+			}
+			if tt.method == http.MethodHead && rec.Body.Len() > 0 {
+				t.Errorf("HEAD retornou corpo: %s", rec.Body)
+			}
+			if tt.status == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != "GET, HEAD" {
+				t.Errorf("Allow = %q, esperado GET, HEAD", rec.Header().Get("Allow"))
+			}
+		})
+	}
+}
+
+func TestHandlerNotModified(t *testing.T) {
+	fake := seededFake()
+	handler := newHandler(t, fake, builder.BuildOptions{})
+
+	first := serve(handler, http.MethodGet, "/app/prod", nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("primeira resposta %d com ETag %q", first.Code, etag)
+	}
+	yamlETag := serve(handler, http.MethodGet, "/app/prod", map[string]string{"Accept": "application/yaml"}).Header().Get("ETag")
+	if yamlETag == etag {
+		t.Errorf("ETag do YAML igual ao do JSON: %s", etag)
+	}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		status      int
+	}{
+		{name: "ETag atual", ifNoneMatch: etag, status: http.StatusNotModified},
+		{name: "ETag fraco", ifNoneMatch: "W/" + etag, status: http.StatusNotModified},
+		{name: "lista de ETags", ifNoneMatch: `"json-outro", ` + etag, status: http.StatusNotModified},
+		{name: "curinga", ifNoneMatch: "*", status: http.StatusNotModified},
+		{name: "ETag de outro formato", ifNoneMatch: yamlETag, status: http.StatusOK},
+		{name: "ETag desconhecido", ifNoneMatch: `"json-outro"`, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(handler, http.MethodGet, "/app/prod", map[string]string{"If-None-Match": tt.ifNoneMatch})
+			if rec.Code != tt.status {
+				t.Errorf("status %d, esperado %d", rec.Code, tt.status)
+			}
+			if tt.status == http.StatusNotModified && rec.Body.Len() > 0 {
+				t.Errorf("304 com corpo: %s", rec.Body)
+			}
+		})
+	}
+
+	fake.Put(ssmtest.Parameter{Name: "/app/prod/debug", Value: "false"})
+	changed := serve(handler, http.MethodGet, "/app/prod", map[string]string{"If-None-Match": etag})
+	if changed.Code != http.StatusOK || changed.Header().Get("ETag") == etag {
+		t.Errorf("após a alteração: status %d com ETag %q, esperado 200 com novo ETag", changed.Code, changed.Header().Get("ETag"))
+	}
+}
+
+func TestHandlerETagIncludesOptions(t *testing.T) {
+	fake := seededFake()
+	plain := newHandler(t, fake, builder.BuildOptions{})
+	withMetadata := newHandler(t, fake, builder.BuildOptions{BuildMetadata: true})
+
+	etag := serve(plain, http.MethodGet, "/app/prod", nil).Header().Get("ETag")
+	rec := serve(withMetadata, http.MethodGet, "/app/prod", map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, esperado 200 com opções diferentes", rec.Code)
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Errorf("ETag %s não reflete as opções", etag)
+	}
+	if !strings.Contains(rec.Body.String(), "_build") {
+		t.Errorf("corpo sem os metadados de construção: %s", rec.Body)
+	}
+}
+
+func TestHandlerBestEffortPartial(t *testing.T) {
+	fake := seededFake()
+	fake.Throttle(func(operation string) bool { return operation == "GetParametersByPath" })
+
+	var warnings []string
+	onWarning := func(message string) { warnings = append(warnings, message) }
+
+	strict := serve(newHandler(t, fake, builder.BuildOptions{OnWarning: onWarning}), http.MethodGet, "/app/prod", nil)
+	if strict.Code != http.StatusBadGateway {
+		t.Errorf("sem BestEffort: status %d, esperado 502", strict.Code)
+	}
+
+	rec := serve(newHandler(t, fake, builder.BuildOptions{BestEffort: true, RootKey: "config", OnWarning: onWarning}), http.MethodGet, "/app/prod", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("com BestEffort: status %d, esperado 200: %s", rec.Code, rec.Body)
+	}
+	if skipped := rec.Header().Get("X-Config-Skipped-Prefixes"); skipped != "/app/prod" {
+		t.Errorf("X-Config-Skipped-Prefixes = %q, esperado /app/prod", skipped)
+	}
+	if !strings.Contains(rec.Body.String(), `"config"`) {
+		t.Errorf("corpo = %s, esperado o documento parcial", rec.Body)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("avisos = %q, esperado um por requisição", warnings)
+	}
+}
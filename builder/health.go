@@ -0,0 +1,104 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// ErrUnhealthy indica que alguma verificação de HealthCheck falhou
+var ErrUnhealthy = errors.New("verificação de saúde falhou")
+
+// Pinger é implementado pelas fontes que verificam a conectividade e as permissões sem
+// carregar a configuração completa. Fontes sem Ping são ignoradas por HealthCheck.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping verifica a conectividade com o Parameter Store e a permissão de leitura do prefixo
+// com uma chamada GetParametersByPath limitada a um parâmetro. Prefixos em formato ARN
+// são convertidos no caminho e verificados como em HealthCheck: sem ARNClient, a região e
+// a conta precisam ser as do cliente padrão.
+func (b *ConfigBuilder) Ping(ctx context.Context, prefix string) error {
+	path, arn := normalizePrefix(prefix)
+	prefixCtx, err := b.withPrefixClient(ctx, prefixOptions{path: path, arn: arn}, BuildOptions{})
+	if err == nil {
+		err = b.ping(prefixCtx, path)
+	}
+	return b.annotate(ctx, err)
+}
+
+// ping executa a chamada de verificação com o cliente associado ao contexto
+func (b *ConfigBuilder) ping(ctx context.Context, prefix string) error {
+	_, err := b.clientFrom(ctx).GetParametersByPath(ctx, &ssm.GetParametersByPathInput{
+		Path:       aws.String(prefix),
+		Recursive:  aws.Bool(true),
+		MaxResults: aws.Int32(1),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao acessar o prefixo %s: %w", prefix, err)
+	}
+	return nil
+}
+
+// HealthCheck verifica, para sondas de prontidão, cada prefixo das opções (com o cliente
+// de cada prefixo) e cada fonte que implementa Pinger. Todas as verificações são
+// executadas; as falhas são reunidas em um erro que satisfaz errors.Is(err, ErrUnhealthy).
+func (b *ConfigBuilder) HealthCheck(ctx context.Context, opts BuildOptions, sources ...Source) error {
+	var errs []error
+	for _, spec := range b.resolvePrefixes(opts) {
+		prefixCtx, err := b.withPrefixClient(ctx, spec, opts)
+		if err == nil {
+			err = b.ping(prefixCtx, spec.path)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i, source := range sources {
+		pinger, ok := source.(Pinger)
+		if !ok {
+			continue
+		}
+		if err := pinger.Ping(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("fonte %d: %w", i, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return b.annotate(ctx, fmt.Errorf("%w: %w", ErrUnhealthy, errors.Join(errs...)))
+	}
+	return nil
+}
+
+// HealthCheck verifica os prefixos e as fontes de todas as camadas, como
+// ConfigBuilder.HealthCheck
+func (c *Chain) HealthCheck(ctx context.Context) error {
+	opts := c.opts
+	opts.Prefixes = nil
+	opts.PrefixSpecs = nil
+
+	var sources []Source
+	for _, layer := range c.layers {
+		if layer.source != nil {
+			sources = append(sources, layer.source)
+		} else {
+			opts.Prefixes = append(opts.Prefixes, layer.prefix)
+		}
+	}
+	return c.builder.HealthCheck(ctx, opts, sources...)
+}
+
+// Ping implementa Pinger verificando se o arquivo existe e pode ser lido
+func (s *FileSource) Ping(ctx context.Context) error {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir o arquivo %s: %w", s.Path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,64 @@
+package builder_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+func TestPing(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/db/host": "localhost"})
+	b := builder.New(fake)
+	b.SetClientLocation("us-east-1", "123456789012")
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		prefix  string
+		wantErr error
+	}{
+		{name: "caminho", prefix: "/app"},
+		{name: "ARN do cliente padrão", prefix: "arn:aws:ssm:us-east-1:123456789012:parameter/app"},
+		{name: "ARN de outra conta", prefix: "arn:aws:ssm:us-east-1:210987654321:parameter/app", wantErr: builder.ErrForeignARN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := b.Ping(ctx, tt.prefix)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Ping(%s): %v", tt.prefix, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Ping(%s): erro %v, esperado %v", tt.prefix, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/db/host": "localhost"})
+	fake.Throttle(func(operation string) bool { return operation == "GetParametersByPath" })
+	b := builder.New(fake)
+
+	err := b.HealthCheck(context.Background(), builder.BuildOptions{Prefixes: []string{"/app", "/other"}},
+		builder.NewFileSource("missing.json"))
+	if !errors.Is(err, builder.ErrUnhealthy) {
+		t.Fatalf("HealthCheck: erro %v, esperado ErrUnhealthy", err)
+	}
+
+	// Todas as verificações são executadas, mesmo após a primeira falha
+	if calls := fake.Calls("GetParametersByPath"); calls != 2 {
+		t.Errorf("GetParametersByPath chamado %d vezes, esperado 2", calls)
+	}
+	for _, want := range []string{"/app", "/other", "missing.json"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("erro %q sem a verificação de %s", err, want)
+		}
+	}
+}
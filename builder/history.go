@@ -0,0 +1,152 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ParameterVersion versão de um parâmetro retornada por GetParameterHistory
+type ParameterVersion struct {
+	Name             string
+	Version          int64
+	Value            string // Valores SecureString são decifrados apenas com withDecryption
+	Type             string
+	Description      string
+	Labels           []string
+	LastModified     time.Time
+	LastModifiedUser string // ARN de quem gravou a versão
+}
+
+// History lista as versões do parâmetro, da mais antiga para a mais recente, para
+// acompanhar a evolução de uma chave sem o console da AWS. O Parameter Store mantém
+// apenas as 100 versões mais recentes. Como em BuildOptions.WithDecryption, valores
+// SecureString só são decifrados com withDecryption.
+func (b *ConfigBuilder) History(ctx context.Context, name string, withDecryption bool) ([]ParameterVersion, error) {
+	versions, err := b.parameterHistory(ctx, name, withDecryption)
+	return versions, b.annotate(ctx, err)
+}
+
+// parameterHistory busca todas as páginas do histórico do parâmetro
+func (b *ConfigBuilder) parameterHistory(ctx context.Context, name string, withDecryption bool) ([]ParameterVersion, error) {
+	var versions []ParameterVersion
+	var nextToken *string
+
+	for {
+		input := &ssm.GetParameterHistoryInput{
+			Name:           aws.String(name),
+			WithDecryption: aws.Bool(withDecryption),
+			NextToken:      nextToken,
+		}
+
+		result, err := b.clientFrom(ctx).GetParameterHistory(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar o histórico do parâmetro %s: %w", name, err)
+		}
+
+		for _, item := range result.Parameters {
+			versions = append(versions, parameterVersion(item))
+		}
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	return versions, nil
+}
+
+// BuildHistory lista as versões de todos os parâmetros sob o prefixo (recursivamente),
+// indexadas pelo nome do parâmetro, decifrando os valores SecureString apenas com withDecryption
+func (b *ConfigBuilder) BuildHistory(ctx context.Context, prefix string, withDecryption bool) (map[string][]ParameterVersion, error) {
+	metadata, err := b.describeParameterMetadata(ctx, b.pathFilter(prefix))
+	if err != nil {
+		return nil, b.annotate(ctx, fmt.Errorf("erro ao listar os parâmetros do prefixo %s: %w", prefix, err))
+	}
+
+	history := make(map[string][]ParameterVersion, len(metadata))
+	for _, item := range metadata {
+		name := aws.ToString(item.Name)
+		versions, err := b.parameterHistory(ctx, name, withDecryption)
+		if err != nil {
+			return nil, b.annotate(ctx, err)
+		}
+		history[name] = versions
+	}
+	return history, nil
+}
+
+// BuildAsOf reconstrói a configuração do prefixo com as versões dos parâmetros vigentes no
+// instante asOf (a última gravada até ele), para investigar incidentes e reproduzir
+// implantações passadas. Parâmetros criados depois de asOf são omitidos; parâmetros já
+// excluídos e versões além das 100 mantidas pelo Parameter Store não podem ser recuperados.
+// Prefixes, PrefixSpecs e MountKeys são ignorados.
+func (b *ConfigBuilder) BuildAsOf(ctx context.Context, prefix string, asOf time.Time, opts BuildOptions) ([]byte, error) {
+	opts.Prefixes = []string{prefix}
+	opts.PrefixSpecs = nil
+	opts.MountKeys = nil
+	return b.build(ctx, opts, func(ctx context.Context, prefix string) ([]types.Parameter, error) {
+		params, err := b.parametersAsOf(ctx, prefix, asOf, opts.WithDecryption)
+		if err != nil {
+			return nil, err
+		}
+		return b.selectParameters(ctx, params, prefix, opts)
+	})
+}
+
+// parametersAsOf retorna, ordenados pelo nome, os parâmetros do prefixo na versão vigente em asOf
+func (b *ConfigBuilder) parametersAsOf(ctx context.Context, prefix string, asOf time.Time, withDecryption bool) ([]types.Parameter, error) {
+	metadata, err := b.describeParameterMetadata(ctx, b.pathFilter(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar os parâmetros do prefixo %s: %w", prefix, err)
+	}
+
+	var params []types.Parameter
+	for _, item := range metadata {
+		versions, err := b.parameterHistory(ctx, aws.ToString(item.Name), withDecryption)
+		if err != nil {
+			return nil, err
+		}
+
+		var current *ParameterVersion
+		for i, version := range versions {
+			if !version.LastModified.After(asOf) && (current == nil || version.Version > current.Version) {
+				current = &versions[i]
+			}
+		}
+		if current == nil {
+			continue
+		}
+		params = append(params, types.Parameter{
+			Name:             aws.String(current.Name),
+			Value:            aws.String(current.Value),
+			Type:             types.ParameterType(current.Type),
+			Version:          current.Version,
+			LastModifiedDate: aws.Time(current.LastModified),
+		})
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		return *params[i].Name < *params[j].Name
+	})
+	return params, nil
+}
+
+// parameterVersion converte o item do histórico retornado pelo SSM
+func parameterVersion(item types.ParameterHistory) ParameterVersion {
+	return ParameterVersion{
+		Name:             aws.ToString(item.Name),
+		Version:          item.Version,
+		Value:            aws.ToString(item.Value),
+		Type:             string(item.Type),
+		Description:      aws.ToString(item.Description),
+		Labels:           item.Labels,
+		LastModified:     aws.ToTime(item.LastModifiedDate),
+		LastModifiedUser: aws.ToString(item.LastModifiedUser),
+	}
+}
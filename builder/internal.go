@@ -1,11 +1,18 @@
 package builder
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
@@ -14,7 +21,7 @@ import (
 )
 
 // buildStructure constrói a estrutura JSON a partir dos parâmetros
-func (b *ConfigBuilder) buildStructure(params []types.Parameter, basePath string, stripPrefix, sortByDependencies bool) map[string]interface{} {
+func (b *ConfigBuilder) buildStructure(params []types.Parameter, values map[string]interface{}, basePath string, stripPrefix, sortByDependencies bool) map[string]interface{} {
 	if len(params) == 0 {
 		return make(map[string]interface{})
 	}
@@ -22,18 +29,18 @@ func (b *ConfigBuilder) buildStructure(params []types.Parameter, basePath string
 	// Organiza os parâmetros por nível
 	levels := b.organizeParametersByLevel(params, basePath, stripPrefix)
 
-	return b.buildGenericStructure(levels)
+	return b.buildGenericStructure(levels, values)
 }
 
 // buildGenericStructure constrói estrutura genérica sem ordenação por dependências
-func (b *ConfigBuilder) buildGenericStructure(levels map[string]map[string]types.Parameter) map[string]interface{} {
+func (b *ConfigBuilder) buildGenericStructure(levels map[string]map[string]types.Parameter, values map[string]interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
 
 	for levelKey, levelParams := range levels {
 		if levelKey == "." {
-			b.processRootLevel(result, levelParams)
+			b.processRootLevel(result, levelParams, values)
 		} else {
-			b.processNestedLevel(result, levelKey, levelParams)
+			b.processNestedLevel(result, levelKey, levelParams, values)
 		}
 	}
 
@@ -41,41 +48,41 @@ func (b *ConfigBuilder) buildGenericStructure(levels map[string]map[string]types
 }
 
 // processRootLevel processa parâmetros no nível raiz
-func (b *ConfigBuilder) processRootLevel(result map[string]interface{}, levelParams map[string]types.Parameter) {
+func (b *ConfigBuilder) processRootLevel(result map[string]interface{}, levelParams map[string]types.Parameter, values map[string]interface{}) {
 	if len(levelParams) > 1 {
 		// Múltiplos parâmetros - array
-		result["items"] = b.buildArrayFromMap(levelParams)
+		result["items"] = b.buildArrayFromMap(levelParams, values)
 	} else {
 		// Único parâmetro - objeto
 		for paramName, param := range levelParams {
-			result[paramName] = b.parseParameterValue(*param.Value)
+			result[paramName] = values[*param.Name]
 		}
 	}
 }
 
 // processNestedLevel processa parâmetros em níveis aninhados
-func (b *ConfigBuilder) processNestedLevel(result map[string]interface{}, levelKey string, levelParams map[string]types.Parameter) {
+func (b *ConfigBuilder) processNestedLevel(result map[string]interface{}, levelKey string, levelParams map[string]types.Parameter, values map[string]interface{}) {
 	if len(levelParams) == 1 {
 		// Único parâmetro
 		for childPath, param := range levelParams {
 			if childPath == "." {
-				result[levelKey] = b.parseParameterValue(*param.Value)
+				result[levelKey] = values[*param.Name]
 			} else {
-				result[levelKey] = b.buildNestedObject(childPath, param)
+				result[levelKey] = b.buildNestedObject(childPath, param, values)
 			}
 		}
 	} else {
 		// Múltiplos parâmetros
 		if b.shouldBeArray(levelParams) {
-			result[levelKey] = b.buildArrayFromMap(levelParams)
+			result[levelKey] = b.buildArrayFromMap(levelParams, values)
 		} else {
-			result[levelKey] = b.buildNestedStructure(levelParams)
+			result[levelKey] = b.buildNestedStructure(levelParams, values)
 		}
 	}
 }
 
 // buildNestedStructure constrói estrutura aninhada complexa
-func (b *ConfigBuilder) buildNestedStructure(levelParams map[string]types.Parameter) map[string]interface{} {
+func (b *ConfigBuilder) buildNestedStructure(levelParams map[string]types.Parameter, values map[string]interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
 
 	for childPath, param := range levelParams {
@@ -85,7 +92,7 @@ func (b *ConfigBuilder) buildNestedStructure(levelParams map[string]types.Parame
 		for i, part := range pathParts {
 			if i == len(pathParts)-1 {
 				// Última parte - valor final
-				current[part] = b.parseParameterValue(*param.Value)
+				current[part] = values[*param.Name]
 			} else {
 				// Parte intermediária - navega ou cria
 				if existing, exists := current[part]; exists {
@@ -110,14 +117,14 @@ func (b *ConfigBuilder) buildNestedStructure(levelParams map[string]types.Parame
 }
 
 // buildNestedObject constrói objeto aninhado simples
-func (b *ConfigBuilder) buildNestedObject(childPath string, param types.Parameter) map[string]interface{} {
+func (b *ConfigBuilder) buildNestedObject(childPath string, param types.Parameter, values map[string]interface{}) map[string]interface{} {
 	pathParts := strings.Split(childPath, "/")
 	result := make(map[string]interface{})
 	current := result
 
 	for i, part := range pathParts {
 		if i == len(pathParts)-1 {
-			current[part] = b.parseParameterValue(*param.Value)
+			current[part] = values[*param.Name]
 		} else {
 			current[part] = make(map[string]interface{})
 			current = current[part].(map[string]interface{})
@@ -147,11 +154,18 @@ func (b *ConfigBuilder) shouldBeArray(levelParams map[string]types.Parameter) bo
 	return firstDepth == 0
 }
 
-// buildArrayFromMap constrói array a partir de mapa de parâmetros
-func (b *ConfigBuilder) buildArrayFromMap(params map[string]types.Parameter) []interface{} {
+// buildArrayFromMap constrói array a partir de mapa de parâmetros, ordenado pelo caminho
+// para que a saída seja estável entre execuções
+func (b *ConfigBuilder) buildArrayFromMap(params map[string]types.Parameter, values map[string]interface{}) []interface{} {
+	paths := make([]string, 0, len(params))
+	for path := range params {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
 	result := make([]interface{}, 0, len(params))
-	for _, param := range params {
-		result = append(result, b.parseParameterValue(*param.Value))
+	for _, path := range paths {
+		result = append(result, values[*params[path].Name])
 	}
 	return result
 }
@@ -206,6 +220,15 @@ func (b *ConfigBuilder) extractRelativePath(fullPath, basePath string, stripPref
 	return fullPath
 }
 
+// parameterNames retorna os nomes dos parâmetros informados
+func (b *ConfigBuilder) parameterNames(params []types.Parameter) []string {
+	names := make([]string, 0, len(params))
+	for _, param := range params {
+		names = append(names, *param.Name)
+	}
+	return names
+}
+
 // getLastPathSegment retorna o último segmento de um path
 func (b *ConfigBuilder) getLastPathSegment(path string) string {
 	parts := strings.Split(path, "/")
@@ -242,24 +265,472 @@ func (b *ConfigBuilder) mergeMaps(dest, src map[string]interface{}) {
 	}
 }
 
-// getParametersByPath recupera parâmetros recursivamente
-func (b *ConfigBuilder) getParametersByPath(ctx context.Context, path string) ([]types.Parameter, error) {
+// partialResult combina o resultado com os prefixos ignorados no modo BestEffort
+func (b *ConfigBuilder) partialResult(data []byte, err error, skipped []PrefixError) ([]byte, error) {
+	if err != nil || len(skipped) == 0 {
+		return data, err
+	}
+	return data, &PartialError{Skipped: skipped}
+}
+
+// mountConfig monta a configuração de um prefixo sob a chave informada, quando houver
+func (b *ConfigBuilder) mountConfig(prefixConfig map[string]interface{}, mount string) map[string]interface{} {
+	if mount == "" {
+		return prefixConfig
+	}
+	return map[string]interface{}{mount: prefixConfig}
+}
+
+// wrapRootKey envolve a configuração sob a chave raiz, quando informada
+func (b *ConfigBuilder) wrapRootKey(configMap map[string]interface{}, rootKey string) map[string]interface{} {
+	if rootKey == "" {
+		return configMap
+	}
+	return map[string]interface{}{rootKey: configMap}
+}
+
+// client retorna o cliente SSM atual sob leitura protegida
+func (b *ConfigBuilder) client() SSMAPI {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.ssmClient
+}
+
+// assembly resultado intermediário da montagem dos prefixos
+type assembly struct {
+	config  map[string]interface{}
+	owners  map[string]string // Caminho da chave (YAML) -> parâmetro de origem
+	params  []types.Parameter // Parâmetros incluídos na configuração
+	fetched []string          // Prefixos montados com sucesso
+	skipped []PrefixError     // Prefixos ignorados no modo BestEffort
+
+	// prefixContexts contexto de cada prefixo de fetched, com o cliente associado por
+	// withPrefixClient; paramContexts o contexto do prefixo de origem de cada parâmetro
+	prefixContexts []context.Context
+	paramContexts  map[string]context.Context
+
+	// keyForms grafia canônica de cada chave (em minúsculas) com KeyCaseFirstSeen
+	keyForms map[string]string
+}
+
+// newAssembly cria uma montagem vazia
+func newAssembly() *assembly {
+	return &assembly{
+		config:        make(map[string]interface{}),
+		owners:        make(map[string]string),
+		keyForms:      make(map[string]string),
+		paramContexts: make(map[string]context.Context),
+	}
+}
+
+// addPrefix registra um prefixo montado, seus parâmetros e o contexto com o cliente dele
+func (a *assembly) addPrefix(ctx context.Context, path string, params []types.Parameter) {
+	a.fetched = append(a.fetched, path)
+	a.prefixContexts = append(a.prefixContexts, ctx)
+	a.params = append(a.params, params...)
+	for _, param := range params {
+		a.paramContexts[*param.Name] = ctx
+	}
+}
+
+// contextOf retorna o contexto do prefixo de origem do parâmetro, para que as chamadas ao
+// SSM sobre ele usem o cliente do prefixo; parâmetros desconhecidos usam ctx
+func (a *assembly) contextOf(ctx context.Context, name string) context.Context {
+	if prefixCtx, ok := a.paramContexts[name]; ok {
+		return prefixCtx
+	}
+	return ctx
+}
+
+// prefixOptions opções efetivas de um prefixo, já combinadas com as opções globais
+type prefixOptions struct {
+	path        string
+	key         string
+	stripPrefix bool
+	rawValues   bool
+	filter      func(name string) bool
+	transform   func(name, value string) (string, error)
+	arn         parameterARN // Região e conta do prefixo informado como ARN
+	client      SSMAPI       // Cliente próprio do prefixo
+	awsProfile  string       // Perfil de credenciais da AWS do prefixo
+}
+
+// resolvePrefixes combina Prefixes e PrefixSpecs, usando as opções globais como padrão
+func (b *ConfigBuilder) resolvePrefixes(opts BuildOptions) []prefixOptions {
+	specs := make([]prefixOptions, 0, len(opts.Prefixes)+len(opts.PrefixSpecs))
+
+	for _, prefix := range opts.Prefixes {
+		path, arn := normalizePrefix(prefix)
+		specs = append(specs, prefixOptions{
+			path:        path,
+			key:         opts.MountKeys[prefix],
+			stripPrefix: opts.StripPrefix,
+			rawValues:   opts.RawValues,
+			filter:      opts.Filter,
+			transform:   opts.Transform,
+			arn:         arn,
+		})
+	}
+
+	for _, prefixSpec := range opts.PrefixSpecs {
+		path, arn := normalizePrefix(prefixSpec.Path)
+		spec := prefixOptions{
+			path:        path,
+			key:         prefixSpec.Key,
+			stripPrefix: opts.StripPrefix,
+			rawValues:   opts.RawValues,
+			filter:      opts.Filter,
+			transform:   opts.Transform,
+			arn:         arn,
+			client:      prefixSpec.Client,
+			awsProfile:  prefixSpec.AWSProfile,
+		}
+		if prefixSpec.StripPrefix != nil {
+			spec.stripPrefix = *prefixSpec.StripPrefix
+		}
+		if prefixSpec.RawValues != nil {
+			spec.rawValues = *prefixSpec.RawValues
+		}
+		if prefixSpec.Filter != nil {
+			spec.filter = prefixSpec.Filter
+		}
+		if prefixSpec.Transform != nil {
+			spec.transform = prefixSpec.Transform
+		}
+		specs = append(specs, spec)
+	}
+
+	return specs
+}
+
+// applyPrefixHooks aplica o filtro, os decifradores e a transformação de valores do prefixo,
+// nessa ordem
+func (b *ConfigBuilder) applyPrefixHooks(ctx context.Context, params []types.Parameter, spec prefixOptions, decryptors []Decryptor) ([]types.Parameter, error) {
+	if spec.filter == nil && spec.transform == nil && len(decryptors) == 0 {
+		return params, nil
+	}
+
+	result := make([]types.Parameter, 0, len(params))
+	var errs []error
+	for _, param := range params {
+		if spec.filter != nil && !spec.filter(*param.Name) {
+			continue
+		}
+		if len(decryptors) > 0 {
+			var err error
+			param, err = b.decryptValue(ctx, param, decryptors)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		if spec.transform != nil {
+			value, err := spec.transform(*param.Name, *param.Value)
+			if err != nil {
+				errs = append(errs, &ParameterError{Name: *param.Name, Err: fmt.Errorf("erro ao transformar o valor: %w", err)})
+				continue
+			}
+			param.Value = aws.String(value)
+		}
+		result = append(result, param)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+// decodeParameters converte o valor de cada parâmetro, indexado pelo nome
+func (b *ConfigBuilder) decodeParameters(params []types.Parameter, spec prefixOptions, opts BuildOptions) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(params))
+	var errs []error
+	for _, param := range params {
+		if format, ok := b.csvFormat(param, opts); ok {
+			records, err := b.parseCSV(*param.Value, format, spec.rawValues)
+			if err != nil {
+				errs = append(errs, &ParameterError{Name: *param.Name, Err: err})
+				continue
+			}
+			values[*param.Name] = records
+			continue
+		}
+		if opts.Codecs != nil {
+			if codec, ok := opts.Codecs.Lookup(*param.Name); ok {
+				value, err := codec.Decode(*param.Value)
+				if err != nil {
+					errs = append(errs, &ParameterError{Name: *param.Name, Err: err})
+					continue
+				}
+				values[*param.Name] = value
+				continue
+			}
+		}
+		if delimiter, ok := b.listDelimiter(param, opts); ok {
+			values[*param.Name] = b.splitList(*param.Value, delimiter)
+			continue
+		}
+		if spec.rawValues {
+			values[*param.Name] = *param.Value
+			continue
+		}
+		values[*param.Name] = b.parseParameterValue(*param.Value)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return values, nil
+}
+
+// listDelimiter retorna o delimitador a ser usado para expandir o parâmetro em lista.
+// ListDelimiters tem precedência; sem correspondência, parâmetros StringList usam ",".
+func (b *ConfigBuilder) listDelimiter(param types.Parameter, opts BuildOptions) (string, bool) {
+	if !opts.ExpandStringLists {
+		return "", false
+	}
+
+	// Padrões mais longos (mais específicos) são avaliados primeiro
+	patterns := make([]string, 0, len(opts.ListDelimiters))
+	for pattern := range opts.ListDelimiters {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, *param.Name); matched {
+			return opts.ListDelimiters[pattern], true
+		}
+	}
+	if param.Type == types.ParameterTypeStringList {
+		return ",", true
+	}
+	return "", false
+}
+
+// splitList separa o valor em uma lista de strings
+func (b *ConfigBuilder) splitList(value, delimiter string) []interface{} {
+	parts := strings.Split(value, delimiter)
+	result := make([]interface{}, 0, len(parts))
+	for _, part := range parts {
+		result = append(result, part)
+	}
+	return result
+}
+
+// fetchFunc obtém os parâmetros de um prefixo
+type fetchFunc func(ctx context.Context, prefix string) ([]types.Parameter, error)
+
+// fetchPrefix recupera os parâmetros de um prefixo aplicando os limites configurados
+func (b *ConfigBuilder) fetchPrefix(ctx context.Context, prefix string, opts BuildOptions) ([]types.Parameter, error) {
+	params, err := b.cachedParameters(ctx, prefix, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.limitDepth(params, prefix, opts)
+}
+
+// selectParameters seleciona, entre parâmetros já buscados, os que pertencem ao prefixo
+func (b *ConfigBuilder) selectParameters(ctx context.Context, params []types.Parameter, prefix string, opts BuildOptions) ([]types.Parameter, error) {
+	base := strings.TrimSuffix(prefix, "/")
+	recursive := opts.Recursive == nil || *opts.Recursive
+
+	var selected []types.Parameter
+	for _, param := range params {
+		if !strings.HasPrefix(*param.Name, base+"/") {
+			continue
+		}
+		if !recursive && strings.Contains(strings.TrimPrefix(*param.Name, base+"/"), "/") {
+			continue
+		}
+		selected = append(selected, param)
+	}
+
+	if opts.MaxParameters > 0 && len(selected) > opts.MaxParameters {
+		if !opts.TruncateParameters {
+			return nil, fmt.Errorf("%w: prefixo %s contém mais de %d parâmetros", ErrTooManyParameters, prefix, opts.MaxParameters)
+		}
+		b.warn(ctx, opts, "prefixo %s truncado em %d parâmetros", prefix, opts.MaxParameters)
+		selected = selected[:opts.MaxParameters]
+	}
+
+	return b.limitDepth(selected, prefix, opts)
+}
+
+// commonRoot retorna o maior path comum a todos os prefixos
+func (b *ConfigBuilder) commonRoot(prefixes []string) string {
+	if len(prefixes) == 0 {
+		return "/"
+	}
+
+	common := strings.Split(strings.Trim(prefixes[0], "/"), "/")
+	for _, prefix := range prefixes[1:] {
+		segments := strings.Split(strings.Trim(prefix, "/"), "/")
+		n := 0
+		for n < len(common) && n < len(segments) && common[n] == segments[n] {
+			n++
+		}
+		common = common[:n]
+	}
+
+	return "/" + strings.Join(common, "/")
+}
+
+// limitDepth aplica MaxDepth aos parâmetros conforme a DepthPolicy. A profundidade é sempre
+// medida a partir do prefixo, independentemente de StripPrefix (global ou do PrefixSpec).
+// Com DepthFlatten, a chave achatada contém "." literais, que os caminhos de Config
+// endereçam escapados ("a.b\.c").
+func (b *ConfigBuilder) limitDepth(params []types.Parameter, basePath string, opts BuildOptions) ([]types.Parameter, error) {
+	if opts.MaxDepth <= 0 {
+		return params, nil
+	}
+
+	result := make([]types.Parameter, 0, len(params))
+	var errs []error
+	for _, param := range params {
+		relative := b.extractRelativePath(*param.Name, basePath, true)
+		segments := strings.Split(relative, "/")
+		if relative == "" || len(segments) <= opts.MaxDepth {
+			result = append(result, param)
+			continue
+		}
+
+		switch opts.DepthPolicy {
+		case DepthSkip:
+			continue
+		case DepthFlatten:
+			flattened := append(segments[:opts.MaxDepth-1:opts.MaxDepth-1], strings.Join(segments[opts.MaxDepth-1:], "."))
+			name := strings.TrimSuffix(*param.Name, relative) + strings.Join(flattened, "/")
+			param.Name = aws.String(name)
+			result = append(result, param)
+		default:
+			errs = append(errs, &ParameterError{Name: *param.Name, Path: relative, Err: fmt.Errorf("excede a profundidade máxima de %d níveis", opts.MaxDepth)})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+// overrideMaps mescla src sobre dest: mapas são mesclados recursivamente e os demais
+// valores (incluindo listas) de src substituem os de dest
+func (b *ConfigBuilder) overrideMaps(dest, src map[string]interface{}) {
+	for key, srcValue := range src {
+		if destMap, ok := dest[key].(map[string]interface{}); ok {
+			if srcMap, ok := srcValue.(map[string]interface{}); ok {
+				b.overrideMaps(destMap, srcMap)
+				continue
+			}
+		}
+		dest[key] = srcValue
+	}
+}
+
+// mergePatch mescla src sobre dest com a semântica de JSON Merge Patch (RFC 7386):
+// valores nulos removem a chave, mapas são mesclados recursivamente e os demais valores
+// (incluindo listas) substituem os de dest
+func (b *ConfigBuilder) mergePatch(dest, src map[string]interface{}) {
+	for key, srcValue := range src {
+		if srcValue == nil {
+			delete(dest, key)
+			continue
+		}
+		if srcMap, ok := srcValue.(map[string]interface{}); ok {
+			destMap, ok := dest[key].(map[string]interface{})
+			if !ok {
+				destMap = make(map[string]interface{})
+			}
+			b.mergePatch(destMap, srcMap)
+			dest[key] = destMap
+			continue
+		}
+		dest[key] = srcValue
+	}
+}
+
+// merge mescla src sobre dest conforme a estratégia
+func (b *ConfigBuilder) merge(dest, src map[string]interface{}, strategy MergeStrategy) {
+	switch strategy {
+	case MergeOverride:
+		b.overrideMaps(dest, src)
+	case MergePatch:
+		b.mergePatch(dest, src)
+	default:
+		b.mergeMaps(dest, src)
+	}
+}
+
+// getParametersByPath recupera parâmetros do path, recursivamente por padrão
+func (b *ConfigBuilder) getParametersByPath(ctx context.Context, path string, opts BuildOptions) ([]types.Parameter, error) {
 	var allParams []types.Parameter
 	var nextToken *string
+	page := 0
+
+	parent := ctx
+	if opts.FetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.FetchTimeout)
+		defer cancel()
+	}
+
+	recursive := true
+	if opts.Recursive != nil {
+		recursive = *opts.Recursive
+	}
 
 	for {
 		input := &ssm.GetParametersByPathInput{
-			Path:      aws.String(path),
-			Recursive: aws.Bool(true),
-			NextToken: nextToken,
+			Path:           aws.String(path),
+			Recursive:      aws.Bool(recursive),
+			WithDecryption: aws.Bool(opts.WithDecryption),
+			NextToken:      nextToken,
+		}
+
+		pageCtx, cancel := ctx, context.CancelFunc(func() {})
+		if opts.PageTimeout > 0 {
+			pageCtx, cancel = context.WithTimeout(ctx, opts.PageTimeout)
 		}
 
-		result, err := b.ssmClient.GetParametersByPath(ctx, input)
+		result, err := b.clientFrom(ctx).GetParametersByPath(pageCtx, input)
+		cancel()
 		if err != nil {
+			if parent.Err() == nil && pageCtx.Err() == context.DeadlineExceeded {
+				timeout := opts.PageTimeout
+				if ctx.Err() == context.DeadlineExceeded {
+					timeout = opts.FetchTimeout
+				}
+				return nil, &TimeoutError{Prefix: path, Timeout: timeout, Err: err}
+			}
 			return nil, err
 		}
 
 		allParams = append(allParams, result.Parameters...)
+		page++
+		countPage(ctx)
+		if opts.OnPage != nil {
+			opts.OnPage(PageProgress{
+				Prefix:     path,
+				Page:       page,
+				Parameters: len(allParams),
+				More:       result.NextToken != nil,
+			})
+		}
+		if opts.MaxParameters > 0 && len(allParams) > opts.MaxParameters {
+			if !opts.TruncateParameters {
+				return nil, fmt.Errorf("%w: prefixo %s contém mais de %d parâmetros", ErrTooManyParameters, path, opts.MaxParameters)
+			}
+			b.warn(ctx, opts, "prefixo %s truncado em %d parâmetros", path, opts.MaxParameters)
+			allParams = allParams[:opts.MaxParameters]
+			break
+		}
 		if result.NextToken == nil {
 			break
 		}
@@ -274,9 +745,135 @@ func (b *ConfigBuilder) getParametersByPath(ctx context.Context, path string) ([
 	return allParams, nil
 }
 
+// describeParameters recupera a descrição de todos os parâmetros sob o path informado
+func (b *ConfigBuilder) describeParameters(ctx context.Context, path string) (map[string]string, error) {
+	metadata, err := b.describeParameterMetadata(ctx, b.pathFilter(path))
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := make(map[string]string)
+	for _, item := range metadata {
+		if item.Name != nil && item.Description != nil {
+			descriptions[*item.Name] = *item.Description
+		}
+	}
+	return descriptions, nil
+}
+
+// pathFilter retorna o filtro de DescribeParameters para todos os parâmetros sob o path
+func (b *ConfigBuilder) pathFilter(path string) []types.ParameterStringFilter {
+	return []types.ParameterStringFilter{
+		{
+			Key:    aws.String("Path"),
+			Option: aws.String("Recursive"),
+			Values: []string{path},
+		},
+	}
+}
+
+// describeParameterMetadata recupera os metadados de todos os parâmetros que atendem aos filtros
+func (b *ConfigBuilder) describeParameterMetadata(ctx context.Context, filters []types.ParameterStringFilter) ([]types.ParameterMetadata, error) {
+	var metadata []types.ParameterMetadata
+	var nextToken *string
+
+	for {
+		input := &ssm.DescribeParametersInput{
+			ParameterFilters: filters,
+			NextToken:        nextToken,
+		}
+
+		result, err := b.clientFrom(ctx).DescribeParameters(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata = append(metadata, result.Parameters...)
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	return metadata, nil
+}
+
+// listTags recupera as tags associadas a um parâmetro
+func (b *ConfigBuilder) listTags(ctx context.Context, name string) (map[string]string, error) {
+	input := &ssm.ListTagsForResourceInput{
+		ResourceType: types.ResourceTypeForTaggingParameter,
+		ResourceId:   aws.String(name),
+	}
+
+	result, err := b.clientFrom(ctx).ListTagsForResource(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(result.TagList))
+	for _, tag := range result.TagList {
+		if tag.Key != nil && tag.Value != nil {
+			tags[*tag.Key] = *tag.Value
+		}
+	}
+	return tags, nil
+}
+
+// buildMetadata monta o bloco de proveniência adicionado quando BuildMetadata está habilitado
+func (b *ConfigBuilder) buildMetadata(asm *assembly) map[string]interface{} {
+	return map[string]interface{}{
+		"timestamp":      time.Now().UTC().Format(time.RFC3339),
+		"prefixes":       asm.fetched,
+		"parameterCount": len(asm.params),
+		"libraryVersion": Version(),
+		"contentHash":    b.contentHash(asm.params),
+	}
+}
+
+// contentHash calcula o hash SHA-256 do nome, versão e valor dos parâmetros informados
+func (b *ConfigBuilder) contentHash(params []types.Parameter) string {
+	sorted := make([]types.Parameter, len(params))
+	copy(sorted, params)
+	sort.Slice(sorted, func(i, j int) bool {
+		return *sorted[i].Name < *sorted[j].Name
+	})
+
+	h := sha256.New()
+	for _, param := range sorted {
+		fmt.Fprintf(h, "%s\x00%d\x00%s\x00", *param.Name, param.Version, aws.ToString(param.Value))
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// attachTagsMetadata adiciona o nó de metadados com as tags de cada parâmetro incluído,
+// consultadas com o cliente do prefixo de origem
+func (b *ConfigBuilder) attachTagsMetadata(ctx context.Context, asm *assembly) error {
+	names := b.parameterNames(asm.params)
+	metadata := make(map[string]interface{}, len(names))
+
+	for _, name := range names {
+		tags, err := b.listTags(asm.contextOf(ctx, name), name)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar tags do parâmetro %s: %w", name, err)
+		}
+		if len(tags) == 0 {
+			continue
+		}
+
+		tagMap := make(map[string]interface{}, len(tags))
+		for key, value := range tags {
+			tagMap[key] = value
+		}
+		metadata[name] = map[string]interface{}{"tags": tagMap}
+	}
+
+	asm.config[MetadataKey] = metadata
+	return nil
+}
+
 // sortTypesByDependencies reordena os tipos com base nas dependências
 func sortTypesByDependencies(schema map[string]interface{}) error {
-	types, ok := schema["types"].(interface{})
+	types, ok := schema["types"].([]interface{})
 	if !ok {
 		return fmt.Errorf("'types' não encontrado ou não é uma lista")
 	}
@@ -330,7 +927,7 @@ func sortTypesByDependencies(schema map[string]interface{}) error {
 	}
 
 	for name := range typeMap {
-		if err != visit(name); err != nil {
+		if err := visit(name); err != nil {
 			return err
 		}
 	}
@@ -340,15 +937,19 @@ func sortTypesByDependencies(schema map[string]interface{}) error {
 	return nil
 }
 
-// buildYAMLStructure constrói a estrutura YAML a partir dos parâmetros
-func (b *ConfigBuilder) buildYAMLStructure(params []types.Parameter, basePath string, stripPrefix bool) (map[string]interface{}, error) {
+// buildYAMLStructure constrói a estrutura YAML a partir dos parâmetros, retornando também
+// o nome do parâmetro que originou cada chave de primeiro nível
+func (b *ConfigBuilder) buildYAMLStructure(params []types.Parameter, basePath string, stripPrefix bool) (map[string]interface{}, map[string]string, error) {
 	result := make(map[string]interface{})
+	owners := make(map[string]string)
+	var errs []error
 
 	for _, param := range params {
 		value := *param.Value
 		relative := b.extractRelativePath(*param.Name, basePath, stripPrefix)
 		if strings.Contains(relative, "/") {
-			return nil, fmt.Errorf("parâmetros aninhados não são suportados para regras YAML: %s", *param.Name)
+			errs = append(errs, &ParameterError{Name: *param.Name, Path: relative, Err: errors.New("parâmetros aninhados não são suportados para regras YAML")})
+			continue
 		}
 		if relative == "" {
 			relative = b.getLastPathSegment(*param.Name)
@@ -359,6 +960,9 @@ func (b *ConfigBuilder) buildYAMLStructure(params []types.Parameter, basePath st
 		err := yaml.Unmarshal([]byte(value), &m)
 		if err == nil {
 			b.mergeMaps(result, m)
+			for key := range m {
+				owners[key] = *param.Name
+			}
 			continue
 		}
 
@@ -366,16 +970,192 @@ func (b *ConfigBuilder) buildYAMLStructure(params []types.Parameter, basePath st
 		var l []interface{}
 		err = yaml.Unmarshal([]byte(value), &l)
 		if err != nil {
-			return nil, fmt.Errorf("falha ao parsear YAML como map ou lista em %s: %w", *param.Name, err)
+			errs = append(errs, &ParameterError{Name: *param.Name, Path: relative, Err: fmt.Errorf("falha ao parsear YAML como map ou lista: %w", err)})
+			continue
 		}
 
 		// Verifica duplicados
 		if _, exists := result[relative]; exists {
-			return nil, fmt.Errorf("chave de regra duplicada: %s", relative)
+			errs = append(errs, &ParameterError{Name: *param.Name, Path: relative, Err: fmt.Errorf("chave de regra duplicada: %s", relative)})
+			continue
 		}
 
 		result[relative] = l
+		owners[relative] = *param.Name
 	}
 
-	return result, nil
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	return result, owners, nil
+}
+
+// marshalJSON serializa o JSON com a indentação e o escape de HTML configurados.
+// Sem JSONOutput a saída é compacta; com JSONOutput o padrão é indentar com dois espaços.
+func marshalJSON(output interface{}, opts BuildOptions) ([]byte, error) {
+	prefix, indent := "", ""
+	if opts.JSONOutput {
+		prefix, indent = opts.JSONPrefix, opts.JSONIndent
+		if indent == "" {
+			indent = "  "
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(!opts.JSONNoHTMLEscape)
+	encoder.SetIndent(prefix, indent)
+	if err := encoder.Encode(output); err != nil {
+		return nil, err
+	}
+
+	// Encode adiciona uma quebra de linha final que json.Marshal não produz
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// marshalNDJSON emite cada elemento de um documento em formato de array (uma única chave
+// contendo uma lista, como "items") em uma linha JSON compacta
+func (b *ConfigBuilder) marshalNDJSON(output map[string]interface{}, opts BuildOptions) ([]byte, error) {
+	var items []interface{}
+	for key, value := range output {
+		list, ok := value.([]interface{})
+		if len(output) != 1 || !ok {
+			return nil, fmt.Errorf("NDJSONOutput requer um documento com uma única chave contendo um array, encontrado %q", key)
+		}
+		items = list
+	}
+
+	var buf bytes.Buffer
+	for _, item := range items {
+		line, err := marshalJSON(item, BuildOptions{JSONNoHTMLEscape: opts.JSONNoHTMLEscape})
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// compress comprime a saída em gzip com a maior taxa de compressão
+func (b *ConfigBuilder) compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("erro ao comprimir a saída: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("erro ao finalizar a compressão: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// keyDescriptions associa cada chave (YAML ou JSONC) à descrição do parâmetro de origem
+func (b *ConfigBuilder) keyDescriptions(ctx context.Context, asm *assembly) (map[string]string, error) {
+	comments := make(map[string]string)
+	for i, prefix := range asm.fetched {
+		descriptions, err := b.describeParameters(asm.prefixContexts[i], prefix)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar descrições do prefixo %s: %w", prefix, err)
+		}
+		for key, name := range asm.owners {
+			if description, ok := descriptions[name]; ok && description != "" {
+				comments[key] = description
+			}
+		}
+	}
+	return comments, nil
+}
+
+// marshalYAMLNode serializa o YAML via yaml.Node, adicionando comentários acima das chaves,
+// ordenando as chaves (SortKeys), deduplicando subárvores repetidas (YAMLAnchors) e
+// separando documentos (YAMLMultiDocument)
+func (b *ConfigBuilder) marshalYAMLNode(output map[string]interface{}, opts BuildOptions, comments map[string]string) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(output); err != nil {
+		return nil, fmt.Errorf("erro ao codificar YAML: %w", err)
+	}
+
+	if len(comments) > 0 {
+		mapping := &node
+		if opts.RootKey != "" {
+			mapping = b.findMappingValue(mapping, opts.RootKey)
+		}
+		if mapping != nil {
+			b.applyComments(mapping, "", comments)
+		}
+	}
+
+	if opts.SortKeys {
+		b.sortYAMLKeys(&node)
+	}
+
+	if opts.YAMLMultiDocument {
+		return b.marshalYAMLDocuments(&node, opts.YAMLAnchors)
+	}
+
+	if opts.YAMLAnchors {
+		b.deduplicateYAML(&node)
+	}
+
+	return yaml.Marshal(&node)
+}
+
+// marshalYAMLDocuments emite um documento YAML (separado por ---) para o valor de cada
+// chave de primeiro nível. Âncoras não atravessam documentos, por isso a deduplicação
+// é aplicada a cada documento isoladamente.
+func (b *ConfigBuilder) marshalYAMLDocuments(mapping *yaml.Node, anchors bool) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, document := mapping.Content[i], mapping.Content[i+1]
+		document.HeadComment = key.HeadComment
+		if anchors {
+			b.deduplicateYAML(document)
+		}
+		if err := encoder.Encode(document); err != nil {
+			return nil, fmt.Errorf("erro ao codificar o documento YAML %s: %w", key.Value, err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("erro ao finalizar os documentos YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// applyComments adiciona os comentários às chaves cujo caminho (separado por "/") está em comments
+func (b *ConfigBuilder) applyComments(mapping *yaml.Node, path string, comments map[string]string) {
+	if mapping.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+		keyPath := key.Value
+		if path != "" {
+			keyPath = path + "/" + key.Value
+		}
+		if comment, ok := comments[keyPath]; ok {
+			key.HeadComment = comment
+		}
+		b.applyComments(mapping.Content[i+1], keyPath, comments)
+	}
+}
+
+// findMappingValue retorna o nó de valor associado à chave em um mapeamento YAML
+func (b *ConfigBuilder) findMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
 }
@@ -0,0 +1,167 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// parameterOwners associa o caminho relativo de cada parâmetro (segmentos separados por
+// "/") ao seu nome. Chaves agrupadas em listas pela montagem não têm correspondência.
+func (b *ConfigBuilder) parameterOwners(params []types.Parameter, basePath string, stripPrefix bool) map[string]string {
+	owners := make(map[string]string, len(params))
+	for _, param := range params {
+		relative := strings.Trim(b.extractRelativePath(*param.Name, basePath, stripPrefix), "/")
+		if relative == "" {
+			relative = b.getLastPathSegment(*param.Name)
+		}
+		owners[relative] = *param.Name
+	}
+	return owners
+}
+
+// jsoncComments monta os comentários de cada chave: a descrição do parâmetro de origem,
+// quando houver, seguida do seu nome
+func (b *ConfigBuilder) jsoncComments(ctx context.Context, asm *assembly, rootKey string) (map[string][]string, error) {
+	descriptions, err := b.keyDescriptions(ctx, asm)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make(map[string][]string, len(asm.owners))
+	for key, name := range asm.owners {
+		var lines []string
+		if description := descriptions[key]; description != "" {
+			lines = append(lines, strings.Split(description, "\n")...)
+		}
+		lines = append(lines, "origem: "+name)
+		if rootKey != "" {
+			key = rootKey + "/" + key
+		}
+		comments[key] = lines
+	}
+	return comments, nil
+}
+
+// marshalJSONC serializa a configuração como JSONC (JSON com comentários), com as chaves
+// ordenadas e a indentação de JSONIndent (padrão: dois espaços). Cada chave originada de
+// um parâmetro é precedida por comentários "//" com a descrição e o nome do parâmetro.
+func (b *ConfigBuilder) marshalJSONC(ctx context.Context, asm *assembly, output map[string]interface{}, opts BuildOptions) ([]byte, error) {
+	comments, err := b.jsoncComments(ctx, asm, opts.RootKey)
+	if err != nil {
+		return nil, err
+	}
+
+	indent := opts.JSONIndent
+	if indent == "" {
+		indent = "  "
+	}
+	w := &jsoncWriter{indent: indent, escapeHTML: !opts.JSONNoHTMLEscape, comments: comments}
+	if err := w.write(output, "", 0); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// jsoncWriter escreve o documento JSONC recursivamente
+type jsoncWriter struct {
+	buf        bytes.Buffer
+	indent     string
+	escapeHTML bool
+	comments   map[string][]string
+}
+
+// write escreve o valor no nível de indentação depth; path é o caminho da chave atual
+func (w *jsoncWriter) write(value interface{}, path string, depth int) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			w.buf.WriteString("{}")
+			return nil
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		w.buf.WriteString("{\n")
+		for i, key := range keys {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "/" + key
+			}
+			for _, line := range w.comments[keyPath] {
+				w.writeIndent(depth + 1)
+				w.buf.WriteString(strings.TrimRight("// "+line, " "))
+				w.buf.WriteByte('\n')
+			}
+			w.writeIndent(depth + 1)
+			if err := w.scalar(key); err != nil {
+				return err
+			}
+			w.buf.WriteString(": ")
+			if err := w.write(v[key], keyPath, depth+1); err != nil {
+				return err
+			}
+			w.separator(i, len(keys))
+		}
+		w.writeIndent(depth)
+		w.buf.WriteByte('}')
+	case []interface{}:
+		if len(v) == 0 {
+			w.buf.WriteString("[]")
+			return nil
+		}
+		w.buf.WriteString("[\n")
+		for i, item := range v {
+			w.writeIndent(depth + 1)
+			itemPath := strconv.Itoa(i)
+			if path != "" {
+				itemPath = path + "/" + itemPath
+			}
+			if err := w.write(item, itemPath, depth+1); err != nil {
+				return err
+			}
+			w.separator(i, len(v))
+		}
+		w.writeIndent(depth)
+		w.buf.WriteByte(']')
+	default:
+		if err := w.scalar(v); err != nil {
+			return fmt.Errorf("erro ao serializar %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// scalar escreve um valor escalar (ou de tipo não reconhecido) com encoding/json
+func (w *jsoncWriter) scalar(value interface{}) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(w.escapeHTML)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	w.buf.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return nil
+}
+
+// writeIndent escreve a indentação do nível informado
+func (w *jsoncWriter) writeIndent(depth int) {
+	w.buf.WriteString(strings.Repeat(w.indent, depth))
+}
+
+// separator encerra o elemento atual, com vírgula quando não é o último
+func (w *jsoncWriter) separator(i, total int) {
+	if i < total-1 {
+		w.buf.WriteByte(',')
+	}
+	w.buf.WriteByte('\n')
+}
@@ -0,0 +1,189 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyCase forma canônica das chaves quando CaseInsensitiveKeys está habilitado
+type KeyCase int
+
+const (
+	// KeyCaseLower converte as chaves para minúsculas
+	KeyCaseLower KeyCase = iota
+	// KeyCaseUpper converte as chaves para maiúsculas
+	KeyCaseUpper
+	// KeyCaseFirstSeen mantém a grafia da primeira ocorrência de cada chave
+	KeyCaseFirstSeen
+)
+
+// KeySanitization política aplicada às chaves com caracteres especiais. Os caracteres
+// considerados especiais dependem do formato de saída: em todos eles, ".", espaços,
+// caracteres de controle e não ASCII; no YAML, também os indicadores como ":", "#" e "&".
+type KeySanitization int
+
+const (
+	// KeySanitizeNone mantém as chaves como estão
+	KeySanitizeNone KeySanitization = iota
+	// KeySanitizeReplace substitui cada caractere especial por KeyReplacement (padrão: "_")
+	KeySanitizeReplace
+	// KeySanitizeEscape codifica cada caractere especial como %XX, byte a byte em UTF-8
+	KeySanitizeEscape
+	// KeySanitizeError retorna erro ao encontrar chaves com caracteres especiais
+	KeySanitizeError
+)
+
+// ErrInvalidKey indica uma chave com caracteres especiais no modo KeySanitizeError
+var ErrInvalidKey = errors.New("chave com caracteres especiais")
+
+// yamlIndicators caracteres com significado especial em chaves YAML
+const yamlIndicators = ":#{}[],&*!|>'\"@`"
+
+// rewriteKeys reescreve recursivamente as chaves com rename, mesclando as chaves que
+// passam a coincidir. As chaves são visitadas em ordem para um resultado estável.
+func (b *ConfigBuilder) rewriteKeys(m map[string]interface{}, path string, rename func(key string) (string, error)) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	result := make(map[string]interface{}, len(m))
+	for _, key := range keys {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "/" + key
+		}
+
+		renamed, err := rename(key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("chave %s: %w", keyPath, err))
+			continue
+		}
+		value, err := b.rewriteValue(m[key], keyPath, rename)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		b.mergeMaps(result, map[string]interface{}{renamed: value})
+	}
+	return result, errors.Join(errs...)
+}
+
+// rewriteValue aplica rewriteKeys aos mapas contidos no valor
+func (b *ConfigBuilder) rewriteValue(value interface{}, path string, rename func(key string) (string, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return b.rewriteKeys(v, path, rename)
+	case []interface{}:
+		var errs []error
+		for i, item := range v {
+			rewritten, err := b.rewriteValue(item, fmt.Sprintf("%s/%d", path, i), rename)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			v[i] = rewritten
+		}
+		return v, errors.Join(errs...)
+	default:
+		return v, nil
+	}
+}
+
+// rewritePath aplica rename a cada segmento de um caminho separado por "/"
+func (b *ConfigBuilder) rewritePath(path string, rename func(key string) (string, error)) (string, error) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		renamed, err := rename(segment)
+		if err != nil {
+			return "", err
+		}
+		segments[i] = renamed
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// rewriteOwners reescreve as chaves do mapa de origem dos parâmetros com rename
+func (b *ConfigBuilder) rewriteOwners(owners map[string]string, rename func(key string) (string, error)) map[string]string {
+	if owners == nil {
+		return nil
+	}
+	result := make(map[string]string, len(owners))
+	for key, name := range owners {
+		if renamed, err := b.rewritePath(key, rename); err == nil {
+			key = renamed
+		}
+		result[key] = name
+	}
+	return result
+}
+
+// canonicalizer retorna a função que converte as chaves para a forma canônica. Com
+// KeyCaseFirstSeen, forms guarda a primeira grafia de cada chave (em minúsculas).
+func (b *ConfigBuilder) canonicalizer(keyCase KeyCase, forms map[string]string) func(key string) (string, error) {
+	return func(key string) (string, error) {
+		switch keyCase {
+		case KeyCaseUpper:
+			return strings.ToUpper(key), nil
+		case KeyCaseFirstSeen:
+			folded := strings.ToLower(key)
+			if form, ok := forms[folded]; ok {
+				return form, nil
+			}
+			forms[folded] = key
+			return key, nil
+		default:
+			return strings.ToLower(key), nil
+		}
+	}
+}
+
+// sanitizer retorna a função que aplica a política de sanitização às chaves, ou nil
+// quando as chaves devem ser mantidas
+func (b *ConfigBuilder) sanitizer(opts BuildOptions) func(key string) (string, error) {
+	if opts.KeySanitization == KeySanitizeNone {
+		return nil
+	}
+
+	yamlOutput := opts.YAMLRules
+	replacement := opts.KeyReplacement
+	if replacement == "" {
+		replacement = "_"
+	}
+
+	return func(key string) (string, error) {
+		var sb strings.Builder
+		for _, r := range key {
+			special := isSpecialKeyRune(r, yamlOutput)
+			switch {
+			case opts.KeySanitization == KeySanitizeEscape && (special || r == '%'):
+				buf := make([]byte, utf8.RuneLen(r))
+				utf8.EncodeRune(buf, r)
+				for _, c := range buf {
+					fmt.Fprintf(&sb, "%%%02X", c)
+				}
+			case !special:
+				sb.WriteRune(r)
+			case opts.KeySanitization == KeySanitizeError:
+				return "", ErrInvalidKey
+			default:
+				sb.WriteString(replacement)
+			}
+		}
+		return sb.String(), nil
+	}
+}
+
+// isSpecialKeyRune indica se o caractere exige sanitização no formato de saída
+func isSpecialKeyRune(r rune, yamlOutput bool) bool {
+	if r == '.' || r > unicode.MaxASCII || unicode.IsSpace(r) || unicode.IsControl(r) {
+		return true
+	}
+	return yamlOutput && strings.ContainsRune(yamlIndicators, r)
+}
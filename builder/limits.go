@@ -0,0 +1,140 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrLimitExceeded indica um valor ou documento acima dos limites de ParseLimits
+var ErrLimitExceeded = errors.New("limite de interpretação excedido")
+
+// ParseLimits limites aplicados aos valores antes de interpretá-los como JSON ou YAML,
+// protegendo serviços que constroem a configuração em tempo de execução contra parâmetros
+// maliciosos ou corrompidos. Zero desabilita o limite correspondente.
+type ParseLimits struct {
+	MaxValueSize    int // Tamanho máximo de cada valor, em bytes
+	MaxNesting      int // Profundidade máxima de aninhamento de objetos e listas em cada valor
+	MaxDocumentSize int // Soma máxima do tamanho dos valores de todos os prefixos, em bytes
+}
+
+// enabled indica se algum limite foi configurado
+func (l ParseLimits) enabled() bool {
+	return l.MaxValueSize > 0 || l.MaxNesting > 0 || l.MaxDocumentSize > 0
+}
+
+// checkLimits verifica o tamanho e o aninhamento dos valores dos parâmetros, sem
+// interpretá-los por completo. O aninhamento é medido com a sintaxe do codec que vai
+// decodificar cada valor.
+func (b *ConfigBuilder) checkLimits(params []types.Parameter, opts BuildOptions) error {
+	limits := opts.Limits
+	var errs []error
+	for _, param := range params {
+		value := *param.Value
+		if limits.MaxValueSize > 0 && len(value) > limits.MaxValueSize {
+			errs = append(errs, &ParameterError{Name: *param.Name, Err: fmt.Errorf("%w: valor com %d bytes (máximo %d)", ErrLimitExceeded, len(value), limits.MaxValueSize)})
+			continue
+		}
+		if limits.MaxNesting <= 0 {
+			continue
+		}
+
+		depth := b.jsonNesting(value)
+		if b.decodesAsYAML(*param.Name, opts) {
+			depth = b.yamlNesting(value)
+		}
+		if depth > limits.MaxNesting {
+			errs = append(errs, &ParameterError{Name: *param.Name, Err: fmt.Errorf("%w: aninhamento %d (máximo %d)", ErrLimitExceeded, depth, limits.MaxNesting)})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// decodesAsYAML indica se o valor do parâmetro será decodificado como YAML: sempre com
+// YAMLRules, ou quando Codecs associa o nome a YAMLCodec (ex.: sufixos ".yaml" e ".yml")
+func (b *ConfigBuilder) decodesAsYAML(name string, opts BuildOptions) bool {
+	if opts.YAMLRules {
+		return true
+	}
+	if opts.Codecs == nil {
+		return false
+	}
+	codec, ok := opts.Codecs.Lookup(name)
+	if !ok {
+		return false
+	}
+	_, isYAML := codec.(yamlCodec)
+	return isYAML
+}
+
+// checkDocumentSize verifica a soma do tamanho dos valores já montados
+func (b *ConfigBuilder) checkDocumentSize(size int, limits ParseLimits) error {
+	if limits.MaxDocumentSize > 0 && size > limits.MaxDocumentSize {
+		return fmt.Errorf("%w: documento com %d bytes (máximo %d)", ErrLimitExceeded, size, limits.MaxDocumentSize)
+	}
+	return nil
+}
+
+// valuesSize soma o tamanho dos valores dos parâmetros
+func (b *ConfigBuilder) valuesSize(params []types.Parameter) int {
+	size := 0
+	for _, param := range params {
+		size += len(*param.Value)
+	}
+	return size
+}
+
+// jsonNesting calcula a profundidade máxima de objetos e listas do JSON percorrendo o
+// texto, ignorando colchetes e chaves dentro de strings. Valores que não são JSON
+// válido resultam na profundidade dos delimitadores encontrados.
+func (b *ConfigBuilder) jsonNesting(value string) int {
+	depth, deepest := 0, 0
+	inString, escaped := false, false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			deepest = max(deepest, depth)
+		case c == '}' || c == ']':
+			depth = max(depth-1, 0)
+		}
+	}
+	return deepest
+}
+
+// yamlNesting calcula a profundidade máxima de mapas e listas do YAML a partir da árvore de
+// nós, sem decodificar os valores nem expandir aliases. Valores inválidos resultam em zero
+// e falham depois, na interpretação.
+func (b *ConfigBuilder) yamlNesting(value string) int {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &node); err != nil {
+		return 0
+	}
+	return b.yamlNodeNesting(&node)
+}
+
+// yamlNodeNesting calcula a profundidade de um nó YAML
+func (b *ConfigBuilder) yamlNodeNesting(node *yaml.Node) int {
+	deepest := 0
+	for _, child := range node.Content {
+		deepest = max(deepest, b.yamlNodeNesting(child))
+	}
+	if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
+		deepest++
+	}
+	return deepest
+}
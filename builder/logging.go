@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Logger recebe as mensagens de log do builder. *log.Logger satisfaz a interface.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// contextKey tipo das chaves de contexto do pacote
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+	clientKey
+	statsKey
+)
+
+// WithLogger retorna um contexto cujas construções registram seus avisos no logger
+// informado, em vez do log padrão. OnWarning continua tendo precedência.
+func WithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// WithRequestID retorna um contexto com o identificador de correlação (requisição, deploy
+// etc.) incluído nos avisos e nos erros das construções feitas com ele
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID retorna o identificador de correlação do contexto, se houver
+func RequestID(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey).(string)
+	return requestID, ok && requestID != ""
+}
+
+// loggerFrom retorna o logger do contexto ou o log padrão
+func loggerFrom(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(loggerKey).(Logger); ok && logger != nil {
+		return logger
+	}
+	return log.Default()
+}
+
+// warn encaminha um aviso para OnWarning ou, na ausência dele, para o logger do contexto
+func (b *ConfigBuilder) warn(ctx context.Context, opts BuildOptions, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if requestID, ok := RequestID(ctx); ok {
+		message = fmt.Sprintf("[%s] %s", requestID, message)
+	}
+	if opts.OnWarning != nil {
+		opts.OnWarning(message)
+		return
+	}
+	loggerFrom(ctx).Printf("config builder: %s", message)
+}
+
+// annotate inclui o identificador de correlação do contexto no erro, preservando a cadeia
+// de erros para errors.Is e errors.As
+func (b *ConfigBuilder) annotate(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if requestID, ok := RequestID(ctx); ok {
+		return fmt.Errorf("requisição %s: %w", requestID, err)
+	}
+	return err
+}
@@ -0,0 +1,311 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// ErrPatchFailed indica que uma operação JSON Patch não pôde ser aplicada
+var ErrPatchFailed = errors.New("falha ao aplicar JSON Patch")
+
+// patchOperation operação de um documento JSON Patch (RFC 6902)
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// patchDocument documento JSON Patch lido de um parâmetro
+type patchDocument struct {
+	name       string
+	operations []patchOperation
+}
+
+// splitPatches separa os parâmetros sob o subcaminho PatchPath do prefixo, que contêm
+// documentos JSON Patch, dos parâmetros de configuração
+func (b *ConfigBuilder) splitPatches(params []types.Parameter, prefix string, opts BuildOptions) ([]types.Parameter, []patchDocument, error) {
+	patchPath := strings.Trim(opts.PatchPath, "/")
+	if patchPath == "" {
+		return params, nil, nil
+	}
+	base := strings.TrimSuffix(prefix, "/") + "/" + patchPath + "/"
+
+	var regular []types.Parameter
+	var patches []patchDocument
+	var errs []error
+	for _, param := range params {
+		if !strings.HasPrefix(*param.Name, base) {
+			regular = append(regular, param)
+			continue
+		}
+
+		var operations []patchOperation
+		if err := json.Unmarshal([]byte(*param.Value), &operations); err != nil {
+			errs = append(errs, &ParameterError{Name: *param.Name, Err: fmt.Errorf("documento JSON Patch inválido: %w", err)})
+			continue
+		}
+		patches = append(patches, patchDocument{name: *param.Name, operations: operations})
+	}
+
+	// Os documentos de um prefixo são aplicados na ordem dos nomes dos parâmetros
+	sort.Slice(patches, func(i, j int) bool {
+		return patches[i].name < patches[j].name
+	})
+	return regular, patches, errors.Join(errs...)
+}
+
+// applyPatches aplica os documentos JSON Patch à configuração montada. Os ponteiros são
+// relativos à árvore final, antes de RootKey.
+func (b *ConfigBuilder) applyPatches(config map[string]interface{}, patches []patchDocument) (map[string]interface{}, error) {
+	var doc interface{} = config
+	for _, patch := range patches {
+		for i, operation := range patch.operations {
+			var err error
+			doc, err = b.applyPatchOperation(doc, operation)
+			if err != nil {
+				return nil, &ParameterError{Name: patch.name, Err: fmt.Errorf("%w: operação %d (%s %s): %w", ErrPatchFailed, i, operation.Op, operation.Path, err)}
+			}
+		}
+	}
+
+	result, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: o documento resultante não é um objeto", ErrPatchFailed)
+	}
+	return result, nil
+}
+
+// applyPatchOperation aplica uma operação ao documento e retorna o documento resultante
+func (b *ConfigBuilder) applyPatchOperation(doc interface{}, operation patchOperation) (interface{}, error) {
+	tokens, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add", "replace", "test":
+		if operation.Value == nil {
+			return nil, errors.New("campo value ausente")
+		}
+		var value interface{}
+		if err := json.Unmarshal(operation.Value, &value); err != nil {
+			return nil, fmt.Errorf("campo value inválido: %w", err)
+		}
+		switch operation.Op {
+		case "add":
+			return patchAdd(doc, tokens, value)
+		case "replace":
+			return patchReplace(doc, tokens, value)
+		default:
+			return doc, patchTest(doc, tokens, value)
+		}
+	case "remove":
+		doc, _, err = patchRemove(doc, tokens)
+		return doc, err
+	case "move", "copy":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if operation.Op == "move" {
+			if strings.HasPrefix(operation.Path+"/", operation.From+"/") && operation.Path != operation.From {
+				return nil, errors.New("não é possível mover um valor para dentro dele mesmo")
+			}
+			doc, value, err = patchRemove(doc, from)
+		} else {
+			value, err = pointerValue(doc, from)
+			value = deepCopyValue(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return patchAdd(doc, tokens, value)
+	default:
+		return nil, fmt.Errorf("operação desconhecida %q", operation.Op)
+	}
+}
+
+// parsePointer divide um JSON Pointer (RFC 6901) em seus segmentos
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("ponteiro %q deve começar com \"/\"", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// pointerValue retorna o valor apontado pelos segmentos
+func pointerValue(doc interface{}, tokens []string) (interface{}, error) {
+	current := doc
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("chave %q não encontrada", token)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("segmento %q aponta para dentro de um valor escalar", token)
+		}
+	}
+	return current, nil
+}
+
+// arrayIndex converte o segmento em um índice entre 0 e max
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("índice de lista inválido %q", token)
+	}
+	return index, nil
+}
+
+// patchContainer percorre os segmentos até o pai do último e aplica leaf a ele,
+// propagando o contêiner resultante de volta até a raiz
+func patchContainer(node interface{}, tokens []string, leaf func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return leaf(node, tokens[0])
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("chave %q não encontrada", tokens[0])
+		}
+		updated, err := patchContainer(child, tokens[1:], leaf)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = updated
+		return n, nil
+	case []interface{}:
+		index, err := arrayIndex(tokens[0], len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := patchContainer(n[index], tokens[1:], leaf)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = updated
+		return n, nil
+	default:
+		return nil, fmt.Errorf("segmento %q aponta para dentro de um valor escalar", tokens[0])
+	}
+}
+
+// patchAdd implementa a operação "add"
+func patchAdd(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return patchContainer(doc, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[token] = value
+			return p, nil
+		case []interface{}:
+			if token == "-" {
+				return append(p, value), nil
+			}
+			index, err := arrayIndex(token, len(p))
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, nil)
+			copy(p[index+1:], p[index:])
+			p[index] = value
+			return p, nil
+		default:
+			return nil, fmt.Errorf("segmento %q aponta para dentro de um valor escalar", token)
+		}
+	})
+}
+
+// patchRemove implementa a operação "remove", retornando também o valor removido
+func patchRemove(doc interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, nil, errors.New("não é possível remover a raiz do documento")
+	}
+
+	var removed interface{}
+	doc, err := patchContainer(doc, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			value, ok := p[token]
+			if !ok {
+				return nil, fmt.Errorf("chave %q não encontrada", token)
+			}
+			removed = value
+			delete(p, token)
+			return p, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(p)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = p[index]
+			return append(p[:index:index], p[index+1:]...), nil
+		default:
+			return nil, fmt.Errorf("segmento %q aponta para dentro de um valor escalar", token)
+		}
+	})
+	return doc, removed, err
+}
+
+// patchReplace implementa a operação "replace"
+func patchReplace(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	doc, _, err := patchRemove(doc, tokens)
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return patchAdd(doc, tokens, value)
+}
+
+// patchTest implementa a operação "test", comparando as representações JSON dos valores
+func patchTest(doc interface{}, tokens []string, expected interface{}) error {
+	actual, err := pointerValue(doc, tokens)
+	if err != nil {
+		return err
+	}
+
+	actualJSON, err := json.Marshal(actual)
+	if err != nil {
+		return err
+	}
+	expectedJSON, err := json.Marshal(expected)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(actualJSON, expectedJSON) {
+		return fmt.Errorf("valor %s diferente do esperado %s", actualJSON, expectedJSON)
+	}
+	return nil
+}
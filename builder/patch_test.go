@@ -0,0 +1,160 @@
+package builder_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+func TestJSONPatch(t *testing.T) {
+	base := map[string]string{
+		"/app/db/host": "localhost",
+		"/app/hosts":   `["a", "b"]`,
+		"/app/paths":   `{"a/b": 1, "m~n": 2}`,
+	}
+	db := func(host string) map[string]interface{} { return map[string]interface{}{"host": host} }
+	paths := map[string]interface{}{"a/b": float64(1), "m~n": float64(2)}
+
+	tests := []struct {
+		name  string
+		patch string
+		want  map[string]interface{}
+	}{
+		{
+			name:  "add em objeto",
+			patch: `[{"op": "add", "path": "/db/port", "value": 5432}]`,
+			want:  map[string]interface{}{"db": map[string]interface{}{"host": "localhost", "port": float64(5432)}, "hosts": []interface{}{"a", "b"}, "paths": paths},
+		},
+		{
+			name:  "add em índice de lista",
+			patch: `[{"op": "add", "path": "/hosts/1", "value": "x"}]`,
+			want:  map[string]interface{}{"db": db("localhost"), "hosts": []interface{}{"a", "x", "b"}, "paths": paths},
+		},
+		{
+			name:  "add no fim da lista com -",
+			patch: `[{"op": "add", "path": "/hosts/-", "value": "c"}]`,
+			want:  map[string]interface{}{"db": db("localhost"), "hosts": []interface{}{"a", "b", "c"}, "paths": paths},
+		},
+		{
+			name:  "remove",
+			patch: `[{"op": "remove", "path": "/hosts/0"}, {"op": "remove", "path": "/paths"}]`,
+			want:  map[string]interface{}{"db": db("localhost"), "hosts": []interface{}{"b"}},
+		},
+		{
+			name:  "replace",
+			patch: `[{"op": "replace", "path": "/db/host", "value": "db.internal"}]`,
+			want:  map[string]interface{}{"db": db("db.internal"), "hosts": []interface{}{"a", "b"}, "paths": paths},
+		},
+		{
+			name:  "move",
+			patch: `[{"op": "move", "from": "/db/host", "path": "/host"}]`,
+			want:  map[string]interface{}{"db": map[string]interface{}{}, "host": "localhost", "hosts": []interface{}{"a", "b"}, "paths": paths},
+		},
+		{
+			name:  "copy",
+			patch: `[{"op": "copy", "from": "/hosts/0", "path": "/primary"}]`,
+			want:  map[string]interface{}{"db": db("localhost"), "hosts": []interface{}{"a", "b"}, "paths": paths, "primary": "a"},
+		},
+		{
+			name:  "test satisfeito",
+			patch: `[{"op": "test", "path": "/hosts", "value": ["a", "b"]}, {"op": "replace", "path": "/db/host", "value": "db.internal"}]`,
+			want:  map[string]interface{}{"db": db("db.internal"), "hosts": []interface{}{"a", "b"}, "paths": paths},
+		},
+		{
+			name:  "escape ~0 e ~1",
+			patch: `[{"op": "replace", "path": "/paths/a~1b", "value": 10}, {"op": "remove", "path": "/paths/m~0n"}]`,
+			want:  map[string]interface{}{"db": db("localhost"), "hosts": []interface{}{"a", "b"}, "paths": map[string]interface{}{"a/b": float64(10)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := ssmtest.New()
+			fake.Seed(base)
+			fake.Seed(map[string]string{"/app/patches/01": tt.patch})
+
+			got, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+				Prefixes:    []string{"/app"},
+				StripPrefix: true,
+				PatchPath:   "patches",
+			})
+			if err != nil {
+				t.Fatalf("BuildMap: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("configuração = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPatchOrder(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/db/host":    "localhost",
+		"/app/patches/02": `[{"op": "replace", "path": "/db/host", "value": "second"}]`,
+		"/app/patches/01": `[{"op": "replace", "path": "/db/host", "value": "first"}]`,
+		"/app/debug":      "true",
+	})
+
+	got, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app"},
+		StripPrefix: true,
+		PatchPath:   "patches",
+	})
+	if err != nil {
+		t.Fatalf("BuildMap: %v", err)
+	}
+	if host := got["db"].(map[string]interface{})["host"]; host != "second" {
+		t.Errorf("db.host = %v, esperado second (documentos aplicados pela ordem dos nomes)", host)
+	}
+}
+
+func TestJSONPatchFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		msg   string
+	}{
+		{name: "test não satisfeito", patch: `[{"op": "test", "path": "/db/host", "value": "other"}, {"op": "remove", "path": "/db"}]`, msg: "diferente do esperado"},
+		{name: "replace de chave ausente", patch: `[{"op": "replace", "path": "/db/port", "value": 1}]`, msg: `"port" não encontrada`},
+		{name: "move para dentro da origem", patch: `[{"op": "move", "from": "/db", "path": "/db/inner"}]`, msg: "dentro dele mesmo"},
+		{name: "índice com zero à esquerda", patch: `[{"op": "add", "path": "/hosts/01", "value": "x"}]`, msg: "índice de lista inválido"},
+		{name: "índice além do fim", patch: `[{"op": "remove", "path": "/hosts/2"}]`, msg: "índice de lista inválido"},
+		{name: "ponteiro sem barra", patch: `[{"op": "remove", "path": "db"}]`, msg: "deve começar"},
+		{name: "value ausente", patch: `[{"op": "add", "path": "/x"}]`, msg: "value ausente"},
+		{name: "operação desconhecida", patch: `[{"op": "merge", "path": "/x", "value": 1}]`, msg: "operação desconhecida"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := ssmtest.New()
+			fake.Seed(map[string]string{
+				"/app/db/host":    "localhost",
+				"/app/hosts":      `["a", "b"]`,
+				"/app/patches/01": tt.patch,
+			})
+
+			_, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+				Prefixes:    []string{"/app"},
+				StripPrefix: true,
+				PatchPath:   "patches",
+			})
+			if !errors.Is(err, builder.ErrPatchFailed) {
+				t.Fatalf("erro %v, esperado ErrPatchFailed", err)
+			}
+			var paramErr *builder.ParameterError
+			if !errors.As(err, &paramErr) || paramErr.Name != "/app/patches/01" {
+				t.Errorf("erro %v, esperado o nome do documento /app/patches/01", err)
+			}
+			if !strings.Contains(err.Error(), tt.msg) {
+				t.Errorf("erro %q, esperado conter %q", err, tt.msg)
+			}
+		})
+	}
+}
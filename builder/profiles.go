@@ -0,0 +1,127 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// ErrProfileNotFound indica que o perfil solicitado não foi registrado
+var ErrProfileNotFound = errors.New("perfil não encontrado")
+
+// ProfileRegistry registro de conjuntos nomeados de BuildOptions
+type ProfileRegistry struct {
+	mu       sync.RWMutex
+	profiles map[string]BuildOptions
+}
+
+// Profiles registro global de perfis compartilhado entre os serviços
+var Profiles = NewProfileRegistry()
+
+// NewProfileRegistry cria um registro de perfis vazio
+func NewProfileRegistry() *ProfileRegistry {
+	return &ProfileRegistry{
+		profiles: make(map[string]BuildOptions),
+	}
+}
+
+// Register registra (ou substitui) o perfil com o nome informado
+func (r *ProfileRegistry) Register(name string, opts BuildOptions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profiles[name] = cloneOptions(opts)
+}
+
+// Get retorna uma cópia das opções do perfil
+func (r *ProfileRegistry) Get(name string) (BuildOptions, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	opts, ok := r.profiles[name]
+	if !ok {
+		return BuildOptions{}, false
+	}
+	return cloneOptions(opts), true
+}
+
+// cloneOptions copia as listas, os mapas e os ponteiros das opções, para que alterações
+// feitas por quem registrou ou obteve o perfil não afetem o registro. Funções, clientes e
+// o CodecRegistry continuam compartilhados.
+func cloneOptions(opts BuildOptions) BuildOptions {
+	opts.Prefixes = slices.Clone(opts.Prefixes)
+	opts.MountKeys = maps.Clone(opts.MountKeys)
+	opts.ListDelimiters = maps.Clone(opts.ListDelimiters)
+	opts.TypeSchema = maps.Clone(opts.TypeSchema)
+	opts.KeyRemaps = slices.Clone(opts.KeyRemaps)
+	opts.Decryptors = slices.Clone(opts.Decryptors)
+	opts.Validators = slices.Clone(opts.Validators)
+	opts.Recursive = clonePointer(opts.Recursive)
+
+	if opts.PrefixSpecs != nil {
+		specs := make([]PrefixSpec, len(opts.PrefixSpecs))
+		for i, spec := range opts.PrefixSpecs {
+			spec.StripPrefix = clonePointer(spec.StripPrefix)
+			spec.RawValues = clonePointer(spec.RawValues)
+			specs[i] = spec
+		}
+		opts.PrefixSpecs = specs
+	}
+	if opts.CSVValues != nil {
+		formats := make(map[string]CSVFormat, len(opts.CSVValues))
+		for pattern, format := range opts.CSVValues {
+			format.Header = slices.Clone(format.Header)
+			formats[pattern] = format
+		}
+		opts.CSVValues = formats
+	}
+	if opts.Conditions != nil {
+		conditions := make(map[string]Condition, len(opts.Conditions))
+		for pattern, condition := range opts.Conditions {
+			condition.Tags = maps.Clone(condition.Tags)
+			conditions[pattern] = condition
+		}
+		opts.Conditions = conditions
+	}
+	return opts
+}
+
+// clonePointer retorna um novo ponteiro com o mesmo valor, ou nil
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	value := *p
+	return &value
+}
+
+// Names retorna os nomes dos perfis registrados em ordem alfabética
+func (r *ProfileRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.profiles))
+	for name := range r.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildWithProfile constrói a configuração com as opções do perfil registrado em Profiles.
+// Quando informados, os prefixos substituem os prefixos do perfil.
+func (b *ConfigBuilder) BuildWithProfile(ctx context.Context, profile string, prefixes ...string) ([]byte, error) {
+	opts, ok := Profiles.Get(profile)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, profile)
+	}
+
+	if len(prefixes) > 0 {
+		opts.Prefixes = prefixes
+	}
+	return b.BuildConfigFromPrefixes(ctx, opts)
+}
@@ -0,0 +1,73 @@
+package builder_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+)
+
+// nopDecryptor decifrador que não reconhece nenhum valor
+type nopDecryptor struct{}
+
+func (nopDecryptor) Decrypt(ctx context.Context, name, value string) (string, bool, error) {
+	return value, false, nil
+}
+
+// profileOptions opções com todas as listas, mapas e ponteiros preenchidos
+func profileOptions() builder.BuildOptions {
+	strip := true
+	recursive := false
+	return builder.BuildOptions{
+		Prefixes:       []string{"/app/base"},
+		Recursive:      &recursive,
+		MountKeys:      map[string]string{"/app/base": "base"},
+		PrefixSpecs:    []builder.PrefixSpec{{Path: "/app/prod", StripPrefix: &strip}},
+		ListDelimiters: map[string]string{"/app/hosts": ";"},
+		CSVValues:      map[string]builder.CSVFormat{"/app/users": {Header: []string{"name", "role"}}},
+		KeyRemaps:      []builder.KeyRemap{{From: "legacy.*", To: "$1"}},
+		Conditions:     map[string]builder.Condition{"debug": {Tags: map[string]string{"env": "dev"}}},
+		TypeSchema:     map[string]builder.ValueType{"port": builder.ValueInt},
+		Decryptors:     []builder.Decryptor{nil},
+		Validators:     []builder.Validator{nil},
+	}
+}
+
+// mutate altera, no lugar, cada lista, mapa e ponteiro das opções
+func mutate(opts builder.BuildOptions) {
+	opts.Prefixes[0] = "/mutated"
+	*opts.Recursive = true
+	opts.MountKeys["/app/base"] = "mutated"
+	opts.PrefixSpecs[0].Path = "/mutated"
+	*opts.PrefixSpecs[0].StripPrefix = false
+	opts.ListDelimiters["/app/hosts"] = ","
+	opts.CSVValues["/app/users"].Header[0] = "mutated"
+	opts.KeyRemaps[0].To = "mutated"
+	opts.Conditions["debug"].Tags["env"] = "mutated"
+	opts.TypeSchema["port"] = builder.ValueString
+	opts.Decryptors[0] = nopDecryptor{}
+	opts.Validators[0] = builder.ValidatorFunc(nil)
+}
+
+func TestProfileRegistryCopies(t *testing.T) {
+	registry := builder.NewProfileRegistry()
+
+	registered := profileOptions()
+	registry.Register("prod", registered)
+	mutate(registered)
+
+	got, ok := registry.Get("prod")
+	if !ok {
+		t.Fatal("perfil prod não encontrado")
+	}
+	if want := profileOptions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("alterar as opções registradas afetou o perfil: %+v", got)
+	}
+
+	mutate(got)
+	again, _ := registry.Get("prod")
+	if want := profileOptions(); !reflect.DeepEqual(again, want) {
+		t.Errorf("alterar as opções obtidas afetou o perfil: %+v", again)
+	}
+}
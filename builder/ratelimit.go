@@ -0,0 +1,178 @@
+package builder
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// SetRateLimit limita as chamadas ao SSM de todas as construções do builder a
+// requestsPerSecond, com rajadas de até burst chamadas (mínimo 1). Use para manter buscas
+// com muitos prefixos abaixo do limite de TPS da conta. Zero desabilita o limite.
+func (b *ConfigBuilder) SetRateLimit(requestsPerSecond float64, burst int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if requestsPerSecond <= 0 {
+		b.limiter = nil
+		return
+	}
+	b.limiter = newRateLimiter(requestsPerSecond, max(burst, 1))
+}
+
+// SetMaxConcurrency limita o número de chamadas simultâneas ao SSM de todas as construções
+// do builder. Zero remove o limite.
+func (b *ConfigBuilder) SetMaxConcurrency(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n <= 0 {
+		b.semaphore = nil
+		return
+	}
+	b.semaphore = make(chan struct{}, n)
+}
+
+// limited envolve o cliente com o limite de taxa e de concorrência do builder, quando
+// configurados
+func (b *ConfigBuilder) limited(client SSMAPI) SSMAPI {
+	b.mu.RLock()
+	limiter, semaphore := b.limiter, b.semaphore
+	b.mu.RUnlock()
+
+	if limiter == nil && semaphore == nil {
+		return client
+	}
+	return &limitedClient{client: client, limiter: limiter, semaphore: semaphore}
+}
+
+// rateLimiter token bucket com reserva: cada chamada consome um token e aguarda até que o
+// saldo fique positivo
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter cria o limitador com o balde cheio
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait reserva um token, aguardando sua disponibilidade ou o cancelamento do contexto
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Devolve o token reservado, que não será utilizado
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// limitedClient aplica o limite de taxa e de concorrência a cada chamada do cliente
+type limitedClient struct {
+	client    SSMAPI
+	limiter   *rateLimiter
+	semaphore chan struct{}
+}
+
+// acquire aguarda o limite de taxa e uma vaga no semáforo, retornando a função que libera a vaga
+func (c *limitedClient) acquire(ctx context.Context) (func(), error) {
+	if c.semaphore != nil {
+		select {
+		case c.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if c.semaphore != nil {
+			<-c.semaphore
+		}
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// GetParameters implementa SSMAPI
+func (c *limitedClient) GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.client.GetParameters(ctx, params, optFns...)
+}
+
+// GetParametersByPath implementa SSMAPI
+func (c *limitedClient) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.client.GetParametersByPath(ctx, params, optFns...)
+}
+
+// DescribeParameters implementa SSMAPI
+func (c *limitedClient) DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.client.DescribeParameters(ctx, params, optFns...)
+}
+
+// ListTagsForResource implementa SSMAPI
+func (c *limitedClient) ListTagsForResource(ctx context.Context, params *ssm.ListTagsForResourceInput, optFns ...func(*ssm.Options)) (*ssm.ListTagsForResourceOutput, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.client.ListTagsForResource(ctx, params, optFns...)
+}
+
+// GetParameterHistory implementa SSMAPI
+func (c *limitedClient) GetParameterHistory(ctx context.Context, params *ssm.GetParameterHistoryInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.client.GetParameterHistory(ctx, params, optFns...)
+}
@@ -0,0 +1,110 @@
+// Package regopolicy avalia políticas Rego (Open Policy Agent) contra as configurações
+// montadas pelo builder, como "sem senhas em texto puro" ou "timeouts menores que 30s".
+//
+//	policy, err := regopolicy.New(ctx, map[string]string{"config.rego": module}, "")
+//	data, err := svc.BuildConfigFromPrefixes(ctx, builder.BuildOptions{
+//		Prefixes:   []string{"/app"},
+//		Validators: []builder.Validator{policy},
+//	})
+//
+// A configuração é o input da política, e cada mensagem produzida pela regra consultada
+// é uma violação:
+//
+//	package config
+//
+//	deny contains msg if {
+//		input.http.timeout >= 30
+//		msg := "http.timeout deve ser menor que 30s"
+//	}
+//
+// O pacote é um módulo próprio (github.com/raywall/go-libs-config/builder/regopolicy),
+// para que as dependências do OPA não sejam exigidas de quem usa apenas o builder.
+package regopolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/rego"
+)
+
+// DefaultQuery regra consultada quando nenhuma é informada
+const DefaultQuery = "data.config.deny"
+
+// ViolationError lista as violações de política encontradas na configuração
+type ViolationError struct {
+	Violations []string
+}
+
+// Error implementa a interface error
+func (e *ViolationError) Error() string {
+	return fmt.Sprintf("%d violação(ões) de política: %s", len(e.Violations), strings.Join(e.Violations, "; "))
+}
+
+// Policy conjunto de políticas Rego compiladas. Implementa builder.Validator e é seguro
+// para uso concorrente.
+type Policy struct {
+	query rego.PreparedEvalQuery
+}
+
+// New compila os módulos Rego (nome do arquivo -> código). A regra consultada deve produzir
+// um conjunto ou lista de mensagens; query vazia usa DefaultQuery.
+func New(ctx context.Context, modules map[string]string, query string) (*Policy, error) {
+	if query == "" {
+		query = DefaultQuery
+	}
+
+	options := []func(*rego.Rego){rego.Query(query)}
+	for name, module := range modules {
+		options = append(options, rego.Module(name, module))
+	}
+
+	prepared, err := rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao compilar as políticas Rego: %w", err)
+	}
+	return &Policy{query: prepared}, nil
+}
+
+// Validate implementa builder.Validator, retornando um *ViolationError com todas as
+// violações em ordem alfabética
+func (p *Policy) Validate(ctx context.Context, config map[string]interface{}) error {
+	results, err := p.query.Eval(ctx, rego.EvalInput(config))
+	if err != nil {
+		return fmt.Errorf("erro ao avaliar as políticas Rego: %w", err)
+	}
+
+	var violations []string
+	for _, result := range results {
+		for _, expression := range result.Expressions {
+			messages, ok := expression.Value.([]interface{})
+			if !ok {
+				return fmt.Errorf("a regra %s deve produzir um conjunto de mensagens", expression.Text)
+			}
+			for _, message := range messages {
+				violations = append(violations, violationMessage(message))
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		sort.Strings(violations)
+		return &ViolationError{Violations: violations}
+	}
+	return nil
+}
+
+// violationMessage formata a mensagem; valores que não são texto são serializados em JSON
+func violationMessage(message interface{}) string {
+	if text, ok := message.(string); ok {
+		return text
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Sprint(message)
+	}
+	return string(data)
+}
@@ -0,0 +1,138 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrRemapConflict indica que o destino de um remapeamento já contém um valor que não pode
+// ser mesclado
+var ErrRemapConflict = errors.New("conflito no remapeamento de chaves")
+
+// remapCapture referência a um segmento capturado no destino de KeyRemap ($1, $2...)
+var remapCapture = regexp.MustCompile(`\$(\d+)`)
+
+// KeyRemap move os valores cujo caminho corresponde a From para To, reorganizando layouts
+// legados do Parameter Store na estrutura esperada pela aplicação sem migrar os parâmetros
+type KeyRemap struct {
+	// From padrão de origem na sintaxe de Config.GetAll (ex.: "legacy.*.db_host")
+	From string
+	// To caminho de destino, separado por "." e com as chaves escapadas como em Config.Get;
+	// $1, $2... referem-se aos segmentos capturados por cada "*" de From
+	// (ex.: "services.$1.db.host")
+	To string
+}
+
+// remapKeys aplica as regras em ordem. Os objetos intermediários do destino são criados
+// quando necessário; objetos de origem que ficam vazios são removidos, e objetos movidos
+// para um destino existente são mesclados a ele.
+func (b *ConfigBuilder) remapKeys(asm *assembly, remaps []KeyRemap, caseInsensitive bool) error {
+	for _, remap := range remaps {
+		config := &Config{data: asm.config, caseInsensitive: caseInsensitive}
+		wildcards := wildcardPositions(remap.From)
+
+		type move struct {
+			from, to string
+			value    interface{}
+		}
+		var moves []move
+		sources := make(map[string]bool)
+		for _, match := range config.GetAll(remap.From) {
+			to := remapCapture.ReplaceAllStringFunc(remap.To, func(ref string) string {
+				n, _ := strconv.Atoi(ref[1:])
+				if n < 1 || n > len(wildcards) {
+					return ref
+				}
+				return EscapeKey(match.Segments[wildcards[n-1]])
+			})
+			if to == match.Path {
+				continue
+			}
+			moves = append(moves, move{from: match.Path, to: to, value: match.Value})
+			sources[match.Path] = true
+		}
+		if len(moves) == 0 {
+			continue
+		}
+
+		// As origens são removidas antes das inserções para permitir trocas entre caminhos
+		pruned, _ := pruneExcluded(asm.config, "", sources)
+		asm.config = pruned.(map[string]interface{})
+		for _, m := range moves {
+			if err := b.insertPath(asm.config, m.to, m.value); err != nil {
+				return fmt.Errorf("%w: %s -> %s: %v", ErrRemapConflict, m.from, m.to, err)
+			}
+			asm.owners = remapOwners(asm.owners, m.from, m.to)
+		}
+	}
+	return nil
+}
+
+// wildcardPositions retorna os índices dos segmentos "*" do padrão
+func wildcardPositions(pattern string) []int {
+	var positions []int
+	for i, segment := range splitPath(pattern) {
+		if segment == "*" {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// insertPath grava o valor no caminho, criando os objetos intermediários. Um objeto já
+// existente no destino recebe o valor por mescla quando ele também é um objeto.
+func (b *ConfigBuilder) insertPath(config map[string]interface{}, path string, value interface{}) error {
+	segments := splitPath(path)
+	current := config
+	for _, segment := range segments[:len(segments)-1] {
+		next, exists := current[segment]
+		if !exists {
+			child := make(map[string]interface{})
+			current[segment] = child
+			current = child
+			continue
+		}
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s não é um objeto", segment)
+		}
+		current = child
+	}
+
+	key := segments[len(segments)-1]
+	existing, exists := current[key]
+	if !exists {
+		current[key] = value
+		return nil
+	}
+	existingMap, ok1 := existing.(map[string]interface{})
+	valueMap, ok2 := value.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return errors.New("o destino já possui um valor")
+	}
+	b.merge(existingMap, valueMap, MergeOverride)
+	return nil
+}
+
+// remapOwners move as origens registradas sob from para to
+func remapOwners(owners map[string]string, from, to string) map[string]string {
+	if len(owners) == 0 {
+		return owners
+	}
+	from = strings.Join(splitPath(from), "/")
+	to = strings.Join(splitPath(to), "/")
+
+	result := make(map[string]string, len(owners))
+	for key, name := range owners {
+		if key == from {
+			key = to
+		} else if rest, ok := strings.CutPrefix(key, from+"/"); ok {
+			key = to + "/" + rest
+		}
+		result[key] = name
+	}
+	return result
+}
@@ -0,0 +1,130 @@
+package builder_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+func TestKeyRemaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		seed   map[string]string
+		remaps []builder.KeyRemap
+		want   map[string]interface{}
+	}{
+		{
+			name: "captura de curinga",
+			seed: map[string]string{
+				"/app/legacy/orders/db_host":  "orders-db",
+				"/app/legacy/billing/db_host": "billing-db",
+				"/app/debug/enabled":          "true",
+			},
+			remaps: []builder.KeyRemap{{From: "legacy.*.db_host", To: "services.$1.db.host"}},
+			want: map[string]interface{}{
+				"services": map[string]interface{}{
+					"billing": map[string]interface{}{"db": map[string]interface{}{"host": "billing-db"}},
+					"orders":  map[string]interface{}{"db": map[string]interface{}{"host": "orders-db"}},
+				},
+				"debug": map[string]interface{}{"enabled": true},
+			},
+		},
+		{
+			name: "segmento capturado com ponto",
+			seed: map[string]string{
+				"/app/legacy/orders.v2/db_host": "orders-db",
+				"/app/debug/enabled":            "true",
+			},
+			remaps: []builder.KeyRemap{{From: "legacy.*.db_host", To: "services.$1.host"}},
+			want: map[string]interface{}{
+				"services": map[string]interface{}{
+					"orders.v2": map[string]interface{}{"host": "orders-db"},
+				},
+				"debug": map[string]interface{}{"enabled": true},
+			},
+		},
+		{
+			name: "origem com chave escapada",
+			seed: map[string]string{
+				"/app/legacy/db.host": "localhost",
+				"/app/debug/enabled":  "true",
+			},
+			remaps: []builder.KeyRemap{{From: `legacy.db\.host`, To: "db.host"}},
+			want: map[string]interface{}{
+				"db":    map[string]interface{}{"host": "localhost"},
+				"debug": map[string]interface{}{"enabled": true},
+			},
+		},
+		{
+			name: "mescla com objeto existente",
+			seed: map[string]string{
+				"/app/legacy/db/host": "localhost",
+				"/app/db/pool/size":   "10",
+			},
+			remaps: []builder.KeyRemap{{From: "legacy.db", To: "db"}},
+			want: map[string]interface{}{
+				"db": map[string]interface{}{
+					"host": "localhost",
+					"pool": map[string]interface{}{"size": float64(10)},
+				},
+			},
+		},
+		{
+			name: "troca entre caminhos",
+			seed: map[string]string{
+				"/app/a/x/value": "1",
+				"/app/b/y/value": "2",
+			},
+			remaps: []builder.KeyRemap{
+				{From: "a", To: "tmp"},
+				{From: "b", To: "a"},
+				{From: "tmp", To: "b"},
+			},
+			want: map[string]interface{}{
+				"a": map[string]interface{}{"y": map[string]interface{}{"value": float64(2)}},
+				"b": map[string]interface{}{"x": map[string]interface{}{"value": float64(1)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := ssmtest.New()
+			fake.Seed(tt.seed)
+
+			got, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+				Prefixes:    []string{"/app"},
+				StripPrefix: true,
+				KeyRemaps:   tt.remaps,
+			})
+			if err != nil {
+				t.Fatalf("BuildMap: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("configuração = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyRemapsConflict(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{
+		"/app/legacy/db/host": "legacy-db",
+		"/app/db/host":        "localhost",
+		"/app/debug/enabled":  "true",
+	})
+
+	_, err := builder.New(fake).BuildMap(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app"},
+		StripPrefix: true,
+		KeyRemaps:   []builder.KeyRemap{{From: "legacy.db.host", To: "db.host"}},
+	})
+	if !errors.Is(err, builder.ErrRemapConflict) {
+		t.Errorf("erro %v, esperado ErrRemapConflict", err)
+	}
+}
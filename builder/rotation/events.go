@@ -0,0 +1,89 @@
+package rotation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// secretSuffix sufixo aleatório que o Secrets Manager acrescenta ao nome no ARN do segredo
+var secretSuffix = regexp.MustCompile(`-[A-Za-z0-9]{6}$`)
+
+// event evento do EventBridge com os campos usados para identificar o recurso alterado
+type event struct {
+	Source     string          `json:"source"`
+	DetailType string          `json:"detail-type"`
+	Resources  []string        `json:"resources"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// eventDetail campos do detalhe dos eventos do Parameter Store, do Secrets Manager e do
+// CloudTrail que identificam o parâmetro ou segredo
+type eventDetail struct {
+	Name              string `json:"name"`
+	RequestParameters struct {
+		Name     string `json:"name"`
+		SecretID string `json:"secretId"`
+	} `json:"requestParameters"`
+	AdditionalEventData struct {
+		SecretID string `json:"SecretId"`
+	} `json:"additionalEventData"`
+}
+
+// changedNames extrai do evento os nomes dos parâmetros e segredos alterados. Segredos
+// informados por ARN são convertidos no nome, sem o sufixo aleatório.
+func changedNames(message []byte) ([]string, error) {
+	var evt event
+	if err := json.Unmarshal(message, &evt); err != nil {
+		return nil, fmt.Errorf("%w: evento inválido: %v", ErrInvalidMessage, err)
+	}
+
+	var detail eventDetail
+	if len(evt.Detail) > 0 {
+		if err := json.Unmarshal(evt.Detail, &detail); err != nil {
+			return nil, fmt.Errorf("%w: detalhe do evento inválido: %v", ErrInvalidMessage, err)
+		}
+	}
+
+	candidates := []string{
+		detail.Name,
+		detail.RequestParameters.Name,
+		detail.RequestParameters.SecretID,
+		detail.AdditionalEventData.SecretID,
+	}
+	candidates = append(candidates, evt.Resources...)
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, candidate := range candidates {
+		name := resourceName(candidate)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// resourceName converte o ARN de um parâmetro ou segredo no nome correspondente
+func resourceName(value string) string {
+	if !strings.HasPrefix(value, "arn:") {
+		return value
+	}
+
+	parts := strings.SplitN(value, ":", 7)
+	switch {
+	case len(parts) >= 6 && parts[2] == "ssm":
+		if path, ok := strings.CutPrefix(parts[5], "parameter"); ok {
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+			return path
+		}
+	case len(parts) == 7 && parts[2] == "secretsmanager" && parts[5] == "secret":
+		return secretSuffix.ReplaceAllString(parts[6], "")
+	}
+	return ""
+}
@@ -0,0 +1,317 @@
+// Package rotation reconstrói a configuração quando parâmetros ou segredos são alterados
+// ou rotacionados, a partir de notificações do SNS, sem esperar a expiração do cache.
+//
+// As notificações esperadas são eventos do EventBridge encaminhados a um tópico SNS:
+// "Parameter Store Change" (SSM), eventos de rotação do Secrets Manager e chamadas de API
+// registradas pelo CloudTrail (PutParameter, PutSecretValue etc.). O Refresher pode ser
+// exposto como endpoint HTTP(S) inscrito no tópico ou receber as mensagens de uma função
+// Lambda ou de uma fila SQS com HandleMessage. Qualquer alteração relevante reconstrói a
+// configuração inteira, com o cache de todos os prefixos invalidado.
+//
+//	refresher := rotation.New(svc, builder.BuildOptions{Prefixes: []string{"/app/prod"}})
+//	refresher.OnChange(func(data []byte) { reload(data) })
+//	if err := refresher.Refresh(ctx); err != nil { ... }
+//	http.Handle("/sns", refresher)
+package rotation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raywall/go-libs-config/builder"
+)
+
+// secretReferencePrefix caminho dos parâmetros que referenciam segredos do Secrets Manager
+const secretReferencePrefix = "/aws/reference/secretsmanager/"
+
+// maxMessageSize tamanho máximo aceito de uma notificação SNS (o limite do SNS é 256 KiB)
+const maxMessageSize = 256 * 1024
+
+// defaultMaxMessageAge idade máxima padrão de uma mensagem SNS aceita
+const defaultMaxMessageAge = time.Hour
+
+// ErrInvalidMessage indica uma mensagem que não é uma notificação SNS ou um evento válido
+var ErrInvalidMessage = errors.New("mensagem de notificação inválida")
+
+// Refresher mantém a configuração construída e a reconstrói quando uma notificação indica
+// a alteração de um parâmetro ou segredo dos seus prefixos. É seguro para uso concorrente.
+type Refresher struct {
+	builder *builder.ConfigBuilder
+	opts    builder.BuildOptions
+
+	mu       sync.RWMutex
+	current  []byte
+	onChange func(data []byte)
+	onError  func(err error)
+
+	// rebuild serializa as reconstruções disparadas por notificações simultâneas
+	rebuild sync.Mutex
+
+	// Affects decide se o parâmetro ou segredo notificado afeta a configuração. O padrão
+	// considera os parâmetros sob algum dos prefixos das opções e os segredos de Secrets,
+	// além dos segredos referenciados por prefixos "/aws/reference/secretsmanager/<nome>".
+	Affects func(name string) bool
+
+	// Secrets nomes ou ARNs dos segredos do Secrets Manager usados pela configuração (ex.:
+	// lidos por parâmetros que os referenciam), cuja rotação também dispara a reconstrução.
+	// As notificações do Secrets Manager trazem o nome do segredo, e não o de um parâmetro.
+	Secrets []string
+
+	// TopicARNs restringe os tópicos SNS aceitos (vazio = qualquer tópico)
+	TopicARNs []string
+
+	// MaxMessageAge idade máxima aceita pelo Timestamp da mensagem SNS, que impede a
+	// reapresentação de mensagens antigas (padrão: 1 hora)
+	MaxMessageAge time.Duration
+
+	// HTTPClient cliente usado para baixar os certificados do SNS e confirmar inscrições
+	// (padrão: http.DefaultClient)
+	HTTPClient *http.Client
+
+	verifier *verifier
+}
+
+// New cria um Refresher para a construção com as opções informadas
+func New(b *builder.ConfigBuilder, opts builder.BuildOptions) *Refresher {
+	r := &Refresher{
+		builder:  b,
+		opts:     opts,
+		verifier: newVerifier(),
+	}
+	r.Affects = r.defaultAffects
+	return r
+}
+
+// OnChange define a função chamada com a nova configuração após cada reconstrução
+func (r *Refresher) OnChange(fn func(data []byte)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = fn
+}
+
+// OnError define a função chamada quando uma reconstrução disparada por notificação falha.
+// A configuração anterior é mantida.
+func (r *Refresher) OnError(fn func(err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onError = fn
+}
+
+// Current retorna a última configuração construída com sucesso
+func (r *Refresher) Current() []byte {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// Refresh invalida o cache de todos os prefixos das opções e reconstrói a configuração
+// inteira, independentemente de qual parâmetro ou segredo foi alterado
+func (r *Refresher) Refresh(ctx context.Context) error {
+	r.rebuild.Lock()
+	defer r.rebuild.Unlock()
+
+	if err := r.builder.Invalidate(ctx, r.opts); err != nil {
+		return err
+	}
+	data, err := r.builder.BuildConfigFromPrefixes(ctx, r.opts)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.current = data
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if onChange != nil {
+		onChange(data)
+	}
+	return nil
+}
+
+// HandleMessage processa uma notificação, no envelope do SNS (corpo HTTP ou registro SQS)
+// ou como o evento do EventBridge já extraído (ex.: Records[].Sns.Message no Lambda), e
+// reconstrói a configuração quando algum dos nomes notificados a afeta. Retorna se houve
+// reconstrução. Envelopes SNS têm a assinatura verificada.
+func (r *Refresher) HandleMessage(ctx context.Context, body []byte) (bool, error) {
+	var envelope snsMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	message := body
+	if envelope.Type != "" {
+		if envelope.Type != "Notification" {
+			return false, fmt.Errorf("%w: tipo %q não é uma notificação", ErrInvalidMessage, envelope.Type)
+		}
+		if err := r.accept(ctx, &envelope); err != nil {
+			return false, err
+		}
+		message = []byte(envelope.Message)
+	}
+
+	names, err := changedNames(message)
+	if err != nil {
+		return false, err
+	}
+	if !slices.ContainsFunc(names, r.Affects) {
+		return false, nil
+	}
+
+	if err := r.Refresh(ctx); err != nil {
+		return false, fmt.Errorf("erro ao reconstruir a configuração após a alteração de %s: %w", strings.Join(names, ", "), err)
+	}
+	return true, nil
+}
+
+// ServeHTTP recebe as mensagens de uma inscrição HTTP(S) do SNS: confirma a inscrição e
+// processa as notificações. Falhas na reconstrução são informadas a OnError e respondidas
+// com sucesso, para que o SNS não reenvie a mensagem.
+func (r *Refresher) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxMessageSize+1))
+	if err != nil || len(body) > maxMessageSize {
+		http.Error(w, "mensagem inválida", http.StatusBadRequest)
+		return
+	}
+
+	var envelope snsMessage
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Type == "" {
+		http.Error(w, "mensagem inválida", http.StatusBadRequest)
+		return
+	}
+
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		if err := r.accept(req.Context(), &envelope); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if err := r.confirm(req.Context(), envelope.SubscribeURL); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	case "Notification":
+		if _, err := r.HandleMessage(req.Context(), body); err != nil {
+			if errors.Is(err, ErrInvalidMessage) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			r.reportError(err)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// accept verifica o tópico, a idade e a assinatura da mensagem SNS
+func (r *Refresher) accept(ctx context.Context, envelope *snsMessage) error {
+	if len(r.TopicARNs) > 0 && !slices.Contains(r.TopicARNs, envelope.TopicArn) {
+		return fmt.Errorf("%w: tópico %s não permitido", ErrInvalidMessage, envelope.TopicArn)
+	}
+	if err := r.checkTimestamp(envelope.Timestamp); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	if err := r.verifier.verify(ctx, r.httpClient(), envelope); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	return nil
+}
+
+// checkTimestamp rejeita mensagens mais antigas que MaxMessageAge ou datadas no futuro
+// além da mesma tolerância
+func (r *Refresher) checkTimestamp(timestamp string) error {
+	sent, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return fmt.Errorf("mensagem com Timestamp inválido: %q", timestamp)
+	}
+
+	maxAge := r.MaxMessageAge
+	if maxAge <= 0 {
+		maxAge = defaultMaxMessageAge
+	}
+	if age := time.Since(sent); age > maxAge || age < -maxAge {
+		return fmt.Errorf("mensagem com Timestamp %s fora da janela de %s", timestamp, maxAge)
+	}
+	return nil
+}
+
+// confirm confirma a inscrição acessando o SubscribeURL, que deve ser um endpoint do SNS
+func (r *Refresher) confirm(ctx context.Context, subscribeURL string) error {
+	if err := validateSNSURL(subscribeURL); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, subscribeURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao confirmar a inscrição: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("erro ao confirmar a inscrição: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// reportError encaminha o erro para OnError, quando definido
+func (r *Refresher) reportError(err error) {
+	r.mu.RLock()
+	onError := r.onError
+	r.mu.RUnlock()
+
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// httpClient retorna o cliente HTTP configurado ou o padrão
+func (r *Refresher) httpClient() *http.Client {
+	if r.HTTPClient != nil {
+		return r.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// defaultAffects indica se o nome é de um parâmetro sob algum dos prefixos das opções ou
+// de um segredo de Secrets ou referenciado por um prefixo "/aws/reference/secretsmanager/"
+func (r *Refresher) defaultAffects(name string) bool {
+	prefixes := append([]string(nil), r.opts.Prefixes...)
+	for _, spec := range r.opts.PrefixSpecs {
+		prefixes = append(prefixes, spec.Path)
+	}
+
+	for _, prefix := range prefixes {
+		if _, path, ok := strings.Cut(prefix, ":parameter"); ok && strings.HasPrefix(prefix, "arn:") {
+			prefix = path
+		}
+		prefix = strings.TrimSuffix(prefix, "/")
+		if name == prefix || strings.HasPrefix(name, prefix+"/") {
+			return true
+		}
+		if secret, ok := strings.CutPrefix(prefix, secretReferencePrefix); ok && name == secret {
+			return true
+		}
+	}
+	for _, secret := range r.Secrets {
+		if name == resourceName(secret) {
+			return true
+		}
+	}
+	return false
+}
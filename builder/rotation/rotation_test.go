@@ -0,0 +1,324 @@
+package rotation_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/rotation"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+const (
+	certURL  = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-test.pem"
+	topicARN = "arn:aws:sns:us-east-1:123456789012:config-changes"
+)
+
+// snsServer simula os endpoints do SNS servidos pelo HTTPClient do Refresher: o
+// certificado autoassinado de assinatura e a confirmação de inscrições
+type snsServer struct {
+	key     *rsa.PrivateKey
+	certPEM []byte
+
+	mu       sync.Mutex
+	requests []string
+}
+
+// newSNSServer gera a chave e o certificado autoassinado usados nas assinaturas
+func newSNSServer(t *testing.T) *snsServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	return &snsServer{key: key, certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// RoundTrip serve o certificado em certURL e responde 200 às demais requisições
+func (s *snsServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req.URL.String())
+	s.mu.Unlock()
+
+	body := []byte("ok")
+	if req.URL.String() == certURL {
+		body = s.certPEM
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+// requested retorna as URLs acessadas pelo Refresher
+func (s *snsServer) requested() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// sign preenche Signature com a assinatura do texto canônico na versão informada
+func (s *snsServer) sign(t *testing.T, msg map[string]string) {
+	t.Helper()
+	fields := []string{"Message", "MessageId", "Subject", "Timestamp", "TopicArn", "Type"}
+	if msg["Type"] != "Notification" {
+		fields = []string{"Message", "MessageId", "SubscribeURL", "Timestamp", "Token", "TopicArn", "Type"}
+	}
+	var sb strings.Builder
+	for _, field := range fields {
+		if value, ok := msg[field]; ok {
+			sb.WriteString(field + "\n" + value + "\n")
+		}
+	}
+
+	hash, digest := crypto.SHA256, sha256.Sum256([]byte(sb.String()))
+	sum := digest[:]
+	if msg["SignatureVersion"] == "1" {
+		sha1Digest := sha1.Sum([]byte(sb.String()))
+		hash, sum = crypto.SHA1, sha1Digest[:]
+	}
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, hash, sum)
+	if err != nil {
+		t.Fatalf("SignPKCS1v15: %v", err)
+	}
+	msg["Signature"] = base64.StdEncoding.EncodeToString(signature)
+}
+
+// notification monta a notificação SNS de um evento "Parameter Store Change"
+func notification(name string) map[string]string {
+	event, _ := json.Marshal(map[string]interface{}{
+		"source":      "aws.ssm",
+		"detail-type": "Parameter Store Change",
+		"detail":      map[string]string{"name": name, "operation": "Update"},
+	})
+	return map[string]string{
+		"Type":             "Notification",
+		"MessageId":        "a1b2c3",
+		"TopicArn":         topicARN,
+		"Message":          string(event),
+		"Timestamp":        time.Now().UTC().Format(time.RFC3339),
+		"SignatureVersion": "2",
+		"SigningCertURL":   certURL,
+	}
+}
+
+// newRefresher cria o Refresher sobre um Parameter Store simulado, com o HTTPClient
+// apontado para o servidor SNS simulado
+func newRefresher(server *snsServer) *rotation.Refresher {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/prod/db/host": "localhost"})
+	r := rotation.New(builder.New(fake), builder.BuildOptions{Prefixes: []string{"/app/prod"}, StripPrefix: true})
+	r.HTTPClient = &http.Client{Transport: server}
+	return r
+}
+
+// encode serializa a mensagem no envelope JSON do SNS
+func encode(t *testing.T, msg map[string]string) []byte {
+	t.Helper()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	return data
+}
+
+func TestHandleMessageSignatureVersions(t *testing.T) {
+	server := newSNSServer(t)
+
+	for _, version := range []string{"1", "2"} {
+		t.Run("SignatureVersion "+version, func(t *testing.T) {
+			r := newRefresher(server)
+			var changes int
+			r.OnChange(func([]byte) { changes++ })
+
+			msg := notification("/app/prod/db/host")
+			msg["SignatureVersion"] = version
+			msg["Subject"] = "alteração"
+			server.sign(t, msg)
+
+			refreshed, err := r.HandleMessage(context.Background(), encode(t, msg))
+			if err != nil {
+				t.Fatalf("HandleMessage: %v", err)
+			}
+			if !refreshed || changes != 1 {
+				t.Errorf("reconstruído = %t com %d chamadas a OnChange, esperado true e 1", refreshed, changes)
+			}
+			if current := string(r.Current()); !strings.Contains(current, "localhost") {
+				t.Errorf("Current = %s, esperado a configuração reconstruída", current)
+			}
+		})
+	}
+}
+
+func TestHandleMessageIgnoresUnrelatedParameter(t *testing.T) {
+	server := newSNSServer(t)
+	r := newRefresher(server)
+
+	msg := notification("/other/db/host")
+	server.sign(t, msg)
+
+	refreshed, err := r.HandleMessage(context.Background(), encode(t, msg))
+	if err != nil || refreshed {
+		t.Errorf("HandleMessage = %t, %v; esperado false, nil", refreshed, err)
+	}
+}
+
+func TestHandleMessageRejected(t *testing.T) {
+	server := newSNSServer(t)
+
+	tests := []struct {
+		name      string
+		topicARNs []string
+		prepare   func(msg map[string]string)
+	}{
+		{
+			name: "mensagem adulterada",
+			prepare: func(msg map[string]string) {
+				server.sign(t, msg)
+				msg["Message"] = strings.Replace(msg["Message"], "/app/prod/db/host", "/app/prod/db/port", 1)
+			},
+		},
+		{
+			name: "certificado fora do SNS",
+			prepare: func(msg map[string]string) {
+				msg["SigningCertURL"] = "https://sns.us-east-1.amazonaws.com.attacker.example/cert.pem"
+				server.sign(t, msg)
+			},
+		},
+		{
+			name: "certificado sem HTTPS",
+			prepare: func(msg map[string]string) {
+				msg["SigningCertURL"] = strings.Replace(certURL, "https://", "http://", 1)
+				server.sign(t, msg)
+			},
+		},
+		{
+			name:      "tópico não permitido",
+			topicARNs: []string{"arn:aws:sns:us-east-1:123456789012:other"},
+			prepare:   func(msg map[string]string) { server.sign(t, msg) },
+		},
+		{
+			name: "Timestamp antigo",
+			prepare: func(msg map[string]string) {
+				msg["Timestamp"] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+				server.sign(t, msg)
+			},
+		},
+		{
+			name: "Timestamp no futuro",
+			prepare: func(msg map[string]string) {
+				msg["Timestamp"] = time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+				server.sign(t, msg)
+			},
+		},
+		{
+			name: "versão de assinatura não suportada",
+			prepare: func(msg map[string]string) {
+				server.sign(t, msg)
+				msg["SignatureVersion"] = "3"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRefresher(server)
+			r.TopicARNs = tt.topicARNs
+
+			msg := notification("/app/prod/db/host")
+			tt.prepare(msg)
+
+			refreshed, err := r.HandleMessage(context.Background(), encode(t, msg))
+			if !errors.Is(err, rotation.ErrInvalidMessage) {
+				t.Errorf("erro %v, esperado ErrInvalidMessage", err)
+			}
+			if refreshed || r.Current() != nil {
+				t.Error("a configuração não deveria ser reconstruída")
+			}
+		})
+	}
+
+	for _, url := range server.requested() {
+		if url != certURL {
+			t.Errorf("requisição para %s, esperado apenas o certificado do SNS", url)
+		}
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	server := newSNSServer(t)
+	subscribeURL := "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&Token=abc"
+
+	confirmation := notification("")
+	confirmation["Type"] = "SubscriptionConfirmation"
+	confirmation["Message"] = "confirme a inscrição"
+	confirmation["Token"] = "abc"
+	confirmation["SubscribeURL"] = subscribeURL
+	server.sign(t, confirmation)
+
+	tampered := notification("/app/prod/db/host")
+	server.sign(t, tampered)
+	tampered["TopicArn"] = "arn:aws:sns:us-east-1:123456789012:other"
+
+	tests := []struct {
+		name   string
+		method string
+		body   []byte
+		want   int
+	}{
+		{name: "confirmação de inscrição", method: http.MethodPost, body: encode(t, confirmation), want: http.StatusOK},
+		{name: "notificação adulterada", method: http.MethodPost, body: encode(t, tampered), want: http.StatusForbidden},
+		{name: "corpo inválido", method: http.MethodPost, body: []byte("{"), want: http.StatusBadRequest},
+		{name: "método não permitido", method: http.MethodGet, want: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRefresher(server)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tt.method, "/sns", bytes.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status %d, esperado %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	confirmed := false
+	for _, url := range server.requested() {
+		confirmed = confirmed || url == subscribeURL
+	}
+	if !confirmed {
+		t.Errorf("SubscribeURL não acessada: %v", server.requested())
+	}
+}
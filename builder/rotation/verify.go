@@ -0,0 +1,183 @@
+package rotation
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// snsHost hosts dos endpoints do SNS que podem servir certificados e confirmar inscrições
+var snsHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// snsMessage envelope das mensagens entregues pelo SNS
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// verifier verifica a assinatura das mensagens SNS, mantendo os certificados já baixados
+type verifier struct {
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// newVerifier cria um verificador sem certificados em cache
+func newVerifier() *verifier {
+	return &verifier{certs: make(map[string]*x509.Certificate)}
+}
+
+// verify confere a assinatura da mensagem com o certificado do SNS (versões 1 e 2)
+func (v *verifier) verify(ctx context.Context, client *http.Client, msg *snsMessage) error {
+	var hash crypto.Hash
+	switch msg.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("versão de assinatura não suportada: %q", msg.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("assinatura inválida: %w", err)
+	}
+
+	cert, err := v.certificate(ctx, client, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("certificado do SNS sem chave RSA")
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(stringToSign(msg)))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(stringToSign(msg)))
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+		return errors.New("assinatura da mensagem não confere")
+	}
+	return nil
+}
+
+// certificate baixa (ou reutiliza) o certificado de assinatura, que deve vir do SNS
+func (v *verifier) certificate(ctx context.Context, client *http.Client, certURL string) (*x509.Certificate, error) {
+	if err := validateSNSURL(certURL); err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	cert, ok := v.certs[certURL]
+	v.mu.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar o certificado do SNS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("erro ao baixar o certificado do SNS: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o certificado do SNS: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("certificado do SNS inválido")
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("certificado do SNS inválido: %w", err)
+	}
+
+	v.mu.Lock()
+	v.certs[certURL] = cert
+	v.mu.Unlock()
+	return cert, nil
+}
+
+// validateSNSURL garante que a URL usa HTTPS e aponta para um endpoint do SNS
+func validateSNSURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || !snsHost.MatchString(parsed.Hostname()) {
+		return fmt.Errorf("URL %q não pertence ao SNS", rawURL)
+	}
+	return nil
+}
+
+// stringToSign monta o texto assinado pelo SNS, com os campos na ordem documentada
+func stringToSign(msg *snsMessage) string {
+	var fields [][2]string
+	switch msg.Type {
+	case "Notification":
+		fields = [][2]string{
+			{"Message", msg.Message},
+			{"MessageId", msg.MessageID},
+			{"Subject", msg.Subject},
+			{"Timestamp", msg.Timestamp},
+			{"TopicArn", msg.TopicArn},
+			{"Type", msg.Type},
+		}
+	default:
+		fields = [][2]string{
+			{"Message", msg.Message},
+			{"MessageId", msg.MessageID},
+			{"SubscribeURL", msg.SubscribeURL},
+			{"Timestamp", msg.Timestamp},
+			{"Token", msg.Token},
+			{"TopicArn", msg.TopicArn},
+			{"Type", msg.Type},
+		}
+	}
+
+	var sb strings.Builder
+	for _, field := range fields {
+		// Subject é omitido quando a notificação não tem assunto
+		if field[0] == "Subject" && field[1] == "" {
+			continue
+		}
+		sb.WriteString(field[0])
+		sb.WriteByte('\n')
+		sb.WriteString(field[1])
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
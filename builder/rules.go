@@ -0,0 +1,283 @@
+package builder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidRules indica um documento de regras malformado
+var ErrInvalidRules = errors.New("documento de regras inválido")
+
+// RuleResult resultado da avaliação de um documento de regras
+type RuleResult struct {
+	Matched []string               // Regras disparadas, como "conjunto/nome", na ordem de avaliação
+	Output  map[string]interface{} // Valores definidos pelas ações das regras disparadas
+}
+
+// rule regra de um conjunto. Dispara quando todas as condições de When e ao menos uma
+// de Any (se houver) são verdadeiras.
+type rule struct {
+	Name string          `yaml:"name"`
+	When []ruleCondition `yaml:"when"`
+	Any  []ruleCondition `yaml:"any"`
+	Then []ruleAction    `yaml:"then"`
+	Stop bool            `yaml:"stop"` // Interrompe o conjunto após disparar
+}
+
+// ruleCondition compara o campo da entrada (caminho com ".") com Value
+type ruleCondition struct {
+	Field string      `yaml:"field"`
+	Op    string      `yaml:"op"`
+	Value interface{} `yaml:"value"`
+}
+
+// ruleAction define (Set) ou acrescenta a uma lista (Append) um valor na saída. O valor
+// é Value ou, quando From é informado, o campo correspondente da entrada.
+type ruleAction struct {
+	Set    string      `yaml:"set"`
+	Append string      `yaml:"append"`
+	Value  interface{} `yaml:"value"`
+	From   string      `yaml:"from"`
+}
+
+// Evaluate executa um documento de regras, como o gerado por BuildYamlFromPrefix, contra
+// a entrada. Cada chave de primeiro nível é um conjunto de regras avaliado em ordem
+// alfabética; as regras de um conjunto são avaliadas na ordem do documento:
+//
+//	discount:
+//	  - name: vip
+//	    when:
+//	      - {field: customer.tier, op: eq, value: vip}
+//	    any:
+//	      - {field: order.total, op: gte, value: 100}
+//	      - {field: customer.years, op: gt, value: 5}
+//	    then:
+//	      - {set: discount.percent, value: 20}
+//	    stop: true
+//
+// Operadores: eq, ne, gt, gte, lt, lte, in, not_in, contains, matches, exists e not_exists.
+func Evaluate(rules []byte, input map[string]interface{}) (*RuleResult, error) {
+	var document map[string][]rule
+	decoder := yaml.NewDecoder(bytes.NewReader(rules))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&document); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRules, err)
+	}
+
+	sets := make([]string, 0, len(document))
+	for set := range document {
+		sets = append(sets, set)
+	}
+	sort.Strings(sets)
+
+	source := NewConfig(input)
+	result := &RuleResult{Output: make(map[string]interface{})}
+	for _, set := range sets {
+		for i, r := range document[set] {
+			name := r.Name
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+
+			matched, err := r.matches(source)
+			if err != nil {
+				return nil, fmt.Errorf("%w: regra %s/%s: %w", ErrInvalidRules, set, name, err)
+			}
+			if !matched {
+				continue
+			}
+
+			for _, action := range r.Then {
+				if err := action.apply(source, result.Output); err != nil {
+					return nil, fmt.Errorf("%w: regra %s/%s: %w", ErrInvalidRules, set, name, err)
+				}
+			}
+			result.Matched = append(result.Matched, set+"/"+name)
+			if r.Stop {
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// matches avalia as condições da regra
+func (r rule) matches(source *Config) (bool, error) {
+	for _, condition := range r.When {
+		ok, err := condition.evaluate(source)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if len(r.Any) == 0 {
+		return true, nil
+	}
+	for _, condition := range r.Any {
+		ok, err := condition.evaluate(source)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// evaluate avalia a condição contra a entrada
+func (c ruleCondition) evaluate(source *Config) (bool, error) {
+	if c.Field == "" {
+		return false, errors.New("condição sem field")
+	}
+	actual, exists := source.Get(c.Field)
+
+	switch c.Op {
+	case "exists":
+		return exists, nil
+	case "not_exists":
+		return !exists, nil
+	case "eq", "":
+		return exists && ruleEqual(actual, c.Value), nil
+	case "ne":
+		return !exists || !ruleEqual(actual, c.Value), nil
+	case "gt", "gte", "lt", "lte":
+		left, ok := ruleNumber(actual)
+		if !exists || !ok {
+			return false, nil
+		}
+		right, ok := ruleNumber(c.Value)
+		if !ok {
+			return false, fmt.Errorf("operador %s requer um valor numérico", c.Op)
+		}
+		switch c.Op {
+		case "gt":
+			return left > right, nil
+		case "gte":
+			return left >= right, nil
+		case "lt":
+			return left < right, nil
+		default:
+			return left <= right, nil
+		}
+	case "in", "not_in":
+		options, ok := c.Value.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("operador %s requer uma lista", c.Op)
+		}
+		found := exists && containsEqual(options, actual)
+		return found == (c.Op == "in"), nil
+	case "contains":
+		switch v := actual.(type) {
+		case string:
+			return strings.Contains(v, fmt.Sprint(c.Value)), nil
+		case []interface{}:
+			return containsEqual(v, c.Value), nil
+		default:
+			return false, nil
+		}
+	case "matches":
+		pattern, ok := c.Value.(string)
+		if !ok {
+			return false, errors.New("operador matches requer uma expressão regular")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		text, ok := actual.(string)
+		return exists && ok && re.MatchString(text), nil
+	default:
+		return false, fmt.Errorf("operador desconhecido %q", c.Op)
+	}
+}
+
+// apply executa a ação, gravando o resultado na saída
+func (a ruleAction) apply(source *Config, output map[string]interface{}) error {
+	value := a.Value
+	if a.From != "" {
+		var ok bool
+		if value, ok = source.Get(a.From); !ok {
+			return fmt.Errorf("campo %s não encontrado na entrada", a.From)
+		}
+	}
+
+	switch {
+	case a.Set != "" && a.Append == "":
+		return setPath(output, a.Set, func(interface{}) interface{} { return value })
+	case a.Append != "" && a.Set == "":
+		return setPath(output, a.Append, func(current interface{}) interface{} {
+			list, _ := current.([]interface{})
+			return append(list, value)
+		})
+	default:
+		return errors.New("a ação deve informar exatamente um entre set e append")
+	}
+}
+
+// setPath grava o valor produzido por update no caminho com "." (chaves escapadas como em
+// Config.Get), criando os mapas intermediários
+func setPath(output map[string]interface{}, path string, update func(current interface{}) interface{}) error {
+	segments := splitPath(path)
+	node := output
+	for _, segment := range segments[:len(segments)-1] {
+		child, exists := node[segment]
+		if !exists {
+			child = make(map[string]interface{})
+			node[segment] = child
+		}
+		next, ok := child.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("caminho %s atravessa um valor que não é objeto", path)
+		}
+		node = next
+	}
+
+	last := segments[len(segments)-1]
+	node[last] = update(node[last])
+	return nil
+}
+
+// ruleEqual compara dois valores, tratando números de tipos diferentes como equivalentes
+func ruleEqual(a, b interface{}) bool {
+	if x, ok := ruleNumber(a); ok {
+		if y, ok := ruleNumber(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// containsEqual indica se a lista contém um valor equivalente segundo ruleEqual
+func containsEqual(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if ruleEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleNumber converte valores numéricos para float64
+func ruleNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
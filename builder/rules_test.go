@@ -0,0 +1,149 @@
+package builder_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+)
+
+// rulesInput entrada comum aos testes de regras
+var rulesInput = map[string]interface{}{
+	"customer": map[string]interface{}{
+		"tier":  "vip",
+		"years": 7,
+		"email": "ana@example.com",
+		"tags":  []interface{}{"early", "beta"},
+	},
+	"order": map[string]interface{}{"total": 150.5, "items": 3},
+}
+
+func TestEvaluateOperators(t *testing.T) {
+	tests := []struct {
+		condition string
+		matched   bool
+	}{
+		{condition: `{field: customer.tier, op: eq, value: vip}`, matched: true},
+		{condition: `{field: customer.tier, value: vip}`, matched: true},
+		{condition: `{field: customer.tier, op: eq, value: gold}`, matched: false},
+		{condition: `{field: customer.tier, op: ne, value: gold}`, matched: true},
+		{condition: `{field: customer.missing, op: ne, value: gold}`, matched: true},
+		{condition: `{field: customer.years, op: eq, value: 7.0}`, matched: true},
+		{condition: `{field: order.total, op: gt, value: 150}`, matched: true},
+		{condition: `{field: order.total, op: gte, value: 150.5}`, matched: true},
+		{condition: `{field: order.items, op: lt, value: 3}`, matched: false},
+		{condition: `{field: order.items, op: lte, value: 3}`, matched: true},
+		{condition: `{field: customer.tier, op: gt, value: 1}`, matched: false},
+		{condition: `{field: customer.tier, op: in, value: [gold, vip]}`, matched: true},
+		{condition: `{field: customer.tier, op: not_in, value: [gold, vip]}`, matched: false},
+		{condition: `{field: customer.missing, op: not_in, value: [gold]}`, matched: true},
+		{condition: `{field: customer.email, op: contains, value: "@example"}`, matched: true},
+		{condition: `{field: customer.tags, op: contains, value: beta}`, matched: true},
+		{condition: `{field: customer.tags, op: contains, value: gamma}`, matched: false},
+		{condition: `{field: customer.email, op: matches, value: "^[a-z]+@"}`, matched: true},
+		{condition: `{field: customer.years, op: matches, value: "7"}`, matched: false},
+		{condition: `{field: customer.tier, op: exists}`, matched: true},
+		{condition: `{field: customer.missing, op: exists}`, matched: false},
+		{condition: `{field: customer.missing, op: not_exists}`, matched: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			rules := "set:\n  - name: r\n    when:\n      - " + tt.condition + "\n    then:\n      - {set: hit, value: true}\n"
+			result, err := builder.Evaluate([]byte(rules), rulesInput)
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if matched := len(result.Matched) == 1; matched != tt.matched {
+				t.Errorf("disparou = %t, esperado %t", matched, tt.matched)
+			}
+		})
+	}
+}
+
+func TestEvaluateActions(t *testing.T) {
+	rules := []byte(`
+shipping:
+  - name: heavy
+    when:
+      - {field: order.items, op: gt, value: 10}
+    then:
+      - {set: shipping.method, value: freight}
+  - name: default
+    then:
+      - {set: shipping.method, value: standard}
+discount:
+  - name: vip
+    when:
+      - {field: customer.tier, op: eq, value: vip}
+    any:
+      - {field: order.total, op: gte, value: 1000}
+      - {field: customer.years, op: gt, value: 5}
+    then:
+      - {set: discount.percent, value: 20}
+      - {append: discount.reasons, value: vip}
+      - {set: discount.contact, from: customer.email}
+    stop: true
+  - name: never
+    then:
+      - {set: discount.percent, value: 0}
+badges:
+  - when:
+      - {field: customer.tags, op: contains, value: early}
+    then:
+      - {append: badges, value: pioneer}
+      - {append: badges, value: tester}
+      - {set: 'labels.tier\.name', from: customer.tier}
+`)
+
+	result, err := builder.Evaluate(rules, rulesInput)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	wantMatched := []string{"badges/0", "discount/vip", "shipping/default"}
+	if !reflect.DeepEqual(result.Matched, wantMatched) {
+		t.Errorf("Matched = %v, esperado %v", result.Matched, wantMatched)
+	}
+	wantOutput := map[string]interface{}{
+		"badges": []interface{}{"pioneer", "tester"},
+		"labels": map[string]interface{}{"tier.name": "vip"},
+		"discount": map[string]interface{}{
+			"percent": 20,
+			"reasons": []interface{}{"vip"},
+			"contact": "ana@example.com",
+		},
+		"shipping": map[string]interface{}{"method": "standard"},
+	}
+	if !reflect.DeepEqual(result.Output, wantOutput) {
+		t.Errorf("Output = %v, esperado %v", result.Output, wantOutput)
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{name: "YAML inválido", rules: "set: [\n"},
+		{name: "campo desconhecido", rules: "set:\n  - name: r\n    unless: []\n"},
+		{name: "operador desconhecido", rules: "set:\n  - when:\n      - {field: customer.tier, op: like, value: v}\n"},
+		{name: "condição sem field", rules: "set:\n  - when:\n      - {op: exists}\n"},
+		{name: "comparação com valor não numérico", rules: "set:\n  - when:\n      - {field: order.total, op: gt, value: muito}\n"},
+		{name: "in sem lista", rules: "set:\n  - when:\n      - {field: customer.tier, op: in, value: vip}\n"},
+		{name: "expressão regular inválida", rules: "set:\n  - when:\n      - {field: customer.email, op: matches, value: '('}\n"},
+		{name: "ação com set e append", rules: "set:\n  - then:\n      - {set: a, append: b, value: 1}\n"},
+		{name: "from ausente na entrada", rules: "set:\n  - then:\n      - {set: a, from: customer.missing}\n"},
+		{name: "caminho atravessa valor escalar", rules: "set:\n  - then:\n      - {set: a, value: 1}\n      - {set: a.b, value: 2}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := builder.Evaluate([]byte(tt.rules), rulesInput)
+			if !errors.Is(err, builder.ErrInvalidRules) {
+				t.Errorf("erro %v, esperado ErrInvalidRules", err)
+			}
+		})
+	}
+}
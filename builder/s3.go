@@ -0,0 +1,119 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// ErrS3ClientNotConfigured indica que nenhum cliente S3 foi configurado no builder
+var ErrS3ClientNotConfigured = errors.New("cliente S3 não configurado")
+
+// S3WriteOptions opções de publicação da configuração no S3
+type S3WriteOptions struct {
+	BuildOptions
+	ContentType string // Sobrepõe o content-type inferido do formato de saída
+	KMSKeyID    string // Habilita SSE-KMS com a chave informada
+}
+
+// SetS3Client define o cliente S3 utilizado por WriteToS3
+func (b *ConfigBuilder) SetS3Client(s3Client *s3.Client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.s3Client = s3Client
+}
+
+// WriteToS3 constrói a configuração e publica o documento em s3://bucket/key.
+// No modo BestEffort, o documento parcial é publicado e o *PartialError é retornado.
+func (b *ConfigBuilder) WriteToS3(ctx context.Context, bucket, key string, opts S3WriteOptions) error {
+	b.mu.RLock()
+	s3Client := b.s3Client
+	b.mu.RUnlock()
+	if s3Client == nil {
+		return ErrS3ClientNotConfigured
+	}
+
+	data, buildErr := b.BuildConfigFromPrefixes(ctx, opts.BuildOptions)
+	var partial *PartialError
+	if buildErr != nil && !errors.As(buildErr, &partial) {
+		return buildErr
+	}
+
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = b.contentType(opts.BuildOptions)
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	}
+	if opts.Gzip {
+		input.ContentEncoding = aws.String("gzip")
+	}
+	if opts.KMSKeyID != "" {
+		input.ServerSideEncryption = s3types.ServerSideEncryptionAwsKms
+		input.SSEKMSKeyId = aws.String(opts.KMSKeyID)
+	}
+
+	if _, err := s3Client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("erro ao publicar a configuração em s3://%s/%s: %w", bucket, key, err)
+	}
+
+	return buildErr
+}
+
+// outputKind formato de saída gerado pelas opções
+type outputKind int
+
+const (
+	outputJSON outputKind = iota
+	outputYAML
+	outputNDJSON
+	outputEnv
+	outputJSONC
+)
+
+// outputFile extensão de arquivo e content-type de um formato de saída
+type outputFile struct {
+	extension   string
+	contentType string
+}
+
+// outputFiles extensão e content-type de cada formato de saída, usados por WriteToS3 e
+// SnapshotToS3
+var outputFiles = map[outputKind]outputFile{
+	outputJSON:   {extension: ".json", contentType: "application/json"},
+	outputYAML:   {extension: ".yaml", contentType: "application/yaml"},
+	outputNDJSON: {extension: ".ndjson", contentType: "application/x-ndjson"},
+	outputEnv:    {extension: ".env", contentType: "text/plain"},
+	outputJSONC:  {extension: ".jsonc", contentType: "text/plain"},
+}
+
+// outputKindOf retorna o formato de saída das opções, na mesma precedência de render
+func (b *ConfigBuilder) outputKindOf(opts BuildOptions) outputKind {
+	switch {
+	case opts.YAMLRules:
+		return outputYAML
+	case opts.NDJSONOutput:
+		return outputNDJSON
+	case opts.EnvOutput != EnvNone:
+		return outputEnv
+	case opts.JSONCOutput:
+		return outputJSONC
+	default:
+		return outputJSON
+	}
+}
+
+// contentType retorna o content-type correspondente ao formato de saída
+func (b *ConfigBuilder) contentType(opts BuildOptions) string {
+	return outputFiles[b.outputKindOf(opts)].contentType
+}
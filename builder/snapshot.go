@@ -0,0 +1,236 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// snapshotLayout formato da data no nome dos snapshots (UTC, ordenável como texto)
+const snapshotLayout = "20060102T150405Z"
+
+// maxDeleteObjects limite de chaves por chamada DeleteObjects
+const maxDeleteObjects = 1000
+
+// SnapshotOptions opções de SnapshotToS3 e ScheduleSnapshots. Cada snapshot é gravado em
+// s3://Bucket/KeyPrefix + data UTC + extensão do formato (ex.: "backups/app/20261017T060000Z.json").
+type SnapshotOptions struct {
+	S3WriteOptions
+	Bucket    string
+	KeyPrefix string        // Prefixo das chaves dos snapshots (ex.: "backups/app/")
+	Interval  time.Duration // Intervalo entre os snapshots de ScheduleSnapshots
+	Retain    int           // Quantidade de snapshots mantidos (0 = sem limite)
+	MaxAge    time.Duration // Remove os snapshots mais antigos que isso (0 = sem limite)
+	OnError   func(err error)
+}
+
+// validate verifica as opções de snapshot
+func (o SnapshotOptions) validate() error {
+	var problems []error
+	if o.Bucket == "" {
+		problems = append(problems, errors.New("Bucket não informado"))
+	}
+	if o.Retain < 0 {
+		problems = append(problems, errors.New("Retain não pode ser negativo"))
+	}
+	if o.MaxAge < 0 {
+		problems = append(problems, errors.New("MaxAge não pode ser negativo"))
+	}
+	if o.Interval < 0 {
+		problems = append(problems, errors.New("Interval não pode ser negativo"))
+	}
+	if err := o.BuildOptions.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+	return errors.Join(problems...)
+}
+
+// SnapshotToS3 constrói a configuração, grava um snapshot com a data atual no nome e remove
+// os snapshots que excedem Retain ou MaxAge, retornando a chave gravada. No modo
+// BestEffort, o snapshot parcial é gravado e o *PartialError retornado, sem remoção de
+// snapshots anteriores.
+func (b *ConfigBuilder) SnapshotToS3(ctx context.Context, opts SnapshotOptions) (string, error) {
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	key := opts.KeyPrefix + now.Format(snapshotLayout) + b.snapshotExtension(opts.BuildOptions)
+	if err := b.WriteToS3(ctx, opts.Bucket, key, opts.S3WriteOptions); err != nil {
+		var partial *PartialError
+		if errors.As(err, &partial) {
+			return key, err
+		}
+		return "", err
+	}
+
+	if err := b.pruneSnapshots(ctx, opts, now); err != nil {
+		return key, b.annotate(ctx, err)
+	}
+	return key, nil
+}
+
+// SnapshotScheduler grava snapshots periódicos em segundo plano; criado por ScheduleSnapshots
+type SnapshotScheduler struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	lastKey string
+	lastErr error
+}
+
+// ScheduleSnapshots grava um snapshot imediatamente e a cada Interval, até o contexto ser
+// cancelado ou Stop ser chamado, para backups recuperáveis por data do Parameter Store. As
+// falhas são entregues a OnError ou, na ausência dele, registradas como aviso; a execução
+// continua no próximo intervalo.
+func (b *ConfigBuilder) ScheduleSnapshots(ctx context.Context, opts SnapshotOptions) (*SnapshotScheduler, error) {
+	if opts.Interval <= 0 {
+		return nil, errors.New("Interval deve ser positivo")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	b.mu.RLock()
+	s3Client := b.s3Client
+	b.mu.RUnlock()
+	if s3Client == nil {
+		return nil, ErrS3ClientNotConfigured
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &SnapshotScheduler{cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			key, err := b.SnapshotToS3(ctx, opts)
+			if ctx.Err() != nil {
+				return
+			}
+			s.record(key, err)
+			if err != nil {
+				if opts.OnError != nil {
+					opts.OnError(err)
+				} else {
+					b.warn(ctx, opts.BuildOptions, "erro ao gravar o snapshot em s3://%s/%s: %v", opts.Bucket, opts.KeyPrefix, err)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s, nil
+}
+
+// record guarda o resultado do último snapshot
+func (s *SnapshotScheduler) record(key string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key != "" {
+		s.lastKey = key
+	}
+	s.lastErr = err
+}
+
+// Last retorna a chave do último snapshot gravado e o erro da última execução
+func (s *SnapshotScheduler) Last() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastKey, s.lastErr
+}
+
+// Stop interrompe o agendamento e aguarda a conclusão do snapshot em andamento
+func (s *SnapshotScheduler) Stop() {
+	s.cancel()
+	<-s.done
+}
+
+// snapshotExtension retorna a extensão do arquivo correspondente ao formato de saída
+func (b *ConfigBuilder) snapshotExtension(opts BuildOptions) string {
+	ext := outputFiles[b.outputKindOf(opts)].extension
+	if opts.Gzip {
+		ext += ".gz"
+	}
+	return ext
+}
+
+// pruneSnapshots remove os snapshots de KeyPrefix que excedem Retain ou MaxAge. Apenas as
+// chaves no formato gerado por SnapshotToS3 são consideradas.
+func (b *ConfigBuilder) pruneSnapshots(ctx context.Context, opts SnapshotOptions, now time.Time) error {
+	if opts.Retain == 0 && opts.MaxAge == 0 {
+		return nil
+	}
+	b.mu.RLock()
+	s3Client := b.s3Client
+	b.mu.RUnlock()
+
+	type snapshot struct {
+		key     string
+		created time.Time
+	}
+	var snapshots []snapshot
+	paginator := s3.NewListObjectsV2Paginator(s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(opts.Bucket),
+		Prefix: aws.String(opts.KeyPrefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("erro ao listar os snapshots em s3://%s/%s: %w", opts.Bucket, opts.KeyPrefix, err)
+		}
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			name := strings.TrimPrefix(key, opts.KeyPrefix)
+			if len(name) < len(snapshotLayout) || strings.Contains(name, "/") {
+				continue
+			}
+			created, err := time.Parse(snapshotLayout, name[:len(snapshotLayout)])
+			if err != nil || !strings.HasPrefix(name[len(snapshotLayout):], ".") {
+				continue
+			}
+			snapshots = append(snapshots, snapshot{key: key, created: created})
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].created.After(snapshots[j].created)
+	})
+	var expired []s3types.ObjectIdentifier
+	for i, s := range snapshots {
+		if (opts.Retain > 0 && i >= opts.Retain) || (opts.MaxAge > 0 && now.Sub(s.created) > opts.MaxAge) {
+			expired = append(expired, s3types.ObjectIdentifier{Key: aws.String(s.key)})
+		}
+	}
+
+	for start := 0; start < len(expired); start += maxDeleteObjects {
+		batch := expired[start:min(start+maxDeleteObjects, len(expired))]
+		result, err := s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(opts.Bucket),
+			Delete: &s3types.Delete{Objects: batch, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao remover snapshots antigos de s3://%s/%s: %w", opts.Bucket, opts.KeyPrefix, err)
+		}
+		if len(result.Errors) > 0 {
+			failed := result.Errors[0]
+			return fmt.Errorf("erro ao remover o snapshot %s: %s", aws.ToString(failed.Key), aws.ToString(failed.Message))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package builder_test
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+// s3Server simula o bucket "backups" do S3 com as operações usadas pelos snapshots
+type s3Server struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	puts    int
+	failPut bool
+}
+
+func (s *s3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/backups/")
+	switch {
+	case r.Method == http.MethodPut:
+		s.puts++
+		if s.failPut {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<Error><Code>AccessDenied</Code><Message>falha simulada</Message></Error>`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.objects[key] = body
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		prefix := r.URL.Query().Get("prefix")
+		fmt.Fprint(w, `<ListBucketResult><Name>backups</Name><IsTruncated>false</IsTruncated>`)
+		for _, name := range s.keys() {
+			if strings.HasPrefix(name, prefix) {
+				fmt.Fprintf(w, `<Contents><Key>%s</Key></Contents>`, name)
+			}
+		}
+		fmt.Fprint(w, `</ListBucketResult>`)
+	case r.Method == http.MethodPost && r.URL.Query().Has("delete"):
+		var request struct {
+			Objects []struct {
+				Key string `xml:"Key"`
+			} `xml:"Object"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, object := range request.Objects {
+			delete(s.objects, object.Key)
+		}
+		fmt.Fprint(w, `<DeleteResult></DeleteResult>`)
+	default:
+		http.Error(w, "operação não suportada", http.StatusNotImplemented)
+	}
+}
+
+// keys retorna as chaves armazenadas em ordem
+func (s *s3Server) keys() []string {
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newSnapshotBuilder cria um builder com parâmetros de exemplo e um cliente S3 apontando
+// para o servidor simulado
+func newSnapshotBuilder(t *testing.T, objects ...string) (*builder.ConfigBuilder, *s3Server) {
+	t.Helper()
+	server := &s3Server{objects: map[string][]byte{}}
+	for _, key := range objects {
+		server.objects[key] = []byte("{}")
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/db/host": "localhost", "/app/debug": "false"})
+	b := builder.New(fake)
+	b.SetS3Client(s3.New(s3.Options{
+		Region:                     "us-east-1",
+		BaseEndpoint:               aws.String(httpServer.URL),
+		UsePathStyle:               true,
+		Credentials:                aws.AnonymousCredentials{},
+		RequestChecksumCalculation: aws.RequestChecksumCalculationWhenRequired,
+	}))
+	return b, server
+}
+
+// snapshotOptions retorna opções de snapshot do prefixo /app no bucket simulado
+func snapshotOptions() builder.SnapshotOptions {
+	opts := builder.SnapshotOptions{Bucket: "backups", KeyPrefix: "app/"}
+	opts.Prefixes = []string{"/app"}
+	opts.StripPrefix = true
+	return opts
+}
+
+func TestSnapshotToS3(t *testing.T) {
+	b, server := newSnapshotBuilder(t)
+
+	key, err := b.SnapshotToS3(context.Background(), snapshotOptions())
+	if err != nil {
+		t.Fatalf("SnapshotToS3: %v", err)
+	}
+	if !regexp.MustCompile(`^app/\d{8}T\d{6}Z\.json$`).MatchString(key) {
+		t.Errorf("chave %q fora do formato app/<data UTC>.json", key)
+	}
+	want := map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}, "debug": false}
+	if got := decode(t, server.objects[key]); !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot = %v, esperado %v", got, want)
+	}
+}
+
+func TestSnapshotToS3Prune(t *testing.T) {
+	now := time.Now().UTC()
+	stamp := func(age time.Duration) string {
+		return "app/" + now.Add(-age).Format("20060102T150405Z") + ".json"
+	}
+	unrelated := []string{"app/latest.json", "app/old/20200101T000000Z.json", "other/20200101T000000Z.json"}
+	seeded := append([]string{stamp(time.Hour), stamp(2 * time.Hour), stamp(48 * time.Hour)}, unrelated...)
+
+	tests := []struct {
+		name   string
+		retain int
+		maxAge time.Duration
+		kept   []string
+	}{
+		{name: "sem limites", kept: []string{stamp(time.Hour), stamp(2 * time.Hour), stamp(48 * time.Hour)}},
+		{name: "Retain", retain: 2, kept: []string{stamp(time.Hour)}},
+		{name: "MaxAge", maxAge: 24 * time.Hour, kept: []string{stamp(time.Hour), stamp(2 * time.Hour)}},
+		{name: "Retain e MaxAge", retain: 3, maxAge: 90 * time.Minute, kept: []string{stamp(time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, server := newSnapshotBuilder(t, seeded...)
+			opts := snapshotOptions()
+			opts.Retain, opts.MaxAge = tt.retain, tt.maxAge
+
+			key, err := b.SnapshotToS3(context.Background(), opts)
+			if err != nil {
+				t.Fatalf("SnapshotToS3: %v", err)
+			}
+
+			want := append(append([]string{key}, tt.kept...), unrelated...)
+			sort.Strings(want)
+			if got := server.keys(); !reflect.DeepEqual(got, want) {
+				t.Errorf("objetos = %v, esperado %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSnapshotOptionsValidation(t *testing.T) {
+	b, _ := newSnapshotBuilder(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		change func(opts *builder.SnapshotOptions)
+		want   string
+	}{
+		{name: "Bucket", change: func(opts *builder.SnapshotOptions) { opts.Bucket = "" }, want: "Bucket não informado"},
+		{name: "Retain", change: func(opts *builder.SnapshotOptions) { opts.Retain = -1 }, want: "Retain não pode ser negativo"},
+		{name: "MaxAge", change: func(opts *builder.SnapshotOptions) { opts.MaxAge = -time.Hour }, want: "MaxAge não pode ser negativo"},
+		{name: "BuildOptions", change: func(opts *builder.SnapshotOptions) { opts.Prefixes = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := snapshotOptions()
+			tt.change(&opts)
+			_, err := b.SnapshotToS3(ctx, opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("erro %v, esperado %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := b.ScheduleSnapshots(ctx, snapshotOptions()); err == nil {
+		t.Error("ScheduleSnapshots sem Interval: esperado erro")
+	}
+	opts := snapshotOptions()
+	opts.Interval = time.Minute
+	if _, err := builder.New(ssmtest.New()).ScheduleSnapshots(ctx, opts); !errors.Is(err, builder.ErrS3ClientNotConfigured) {
+		t.Errorf("ScheduleSnapshots sem cliente S3: erro %v, esperado ErrS3ClientNotConfigured", err)
+	}
+}
+
+func TestScheduleSnapshots(t *testing.T) {
+	b, server := newSnapshotBuilder(t)
+	opts := snapshotOptions()
+	opts.Interval = 10 * time.Millisecond
+
+	scheduler, err := b.ScheduleSnapshots(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("ScheduleSnapshots: %v", err)
+	}
+	puts := func() int {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return server.puts
+	}
+	waitFor(t, func() bool { return puts() >= 3 })
+	scheduler.Stop()
+
+	key, err := scheduler.Last()
+	if err != nil || key == "" {
+		t.Errorf("Last = %q, %v; esperado a chave do último snapshot", key, err)
+	}
+	before := puts()
+	time.Sleep(3 * opts.Interval)
+	if after := puts(); after != before {
+		t.Errorf("snapshots gravados após Stop: %d, antes %d", after, before)
+	}
+}
+
+func TestScheduleSnapshotsOnError(t *testing.T) {
+	b, server := newSnapshotBuilder(t)
+	server.failPut = true
+
+	errs := make(chan error, 10)
+	opts := snapshotOptions()
+	opts.Interval = 10 * time.Millisecond
+	opts.OnError = func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	scheduler, err := b.ScheduleSnapshots(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("ScheduleSnapshots: %v", err)
+	}
+	defer scheduler.Stop()
+
+	// A execução continua após a falha
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if !strings.Contains(err.Error(), "s3://backups/app/") {
+				t.Errorf("erro %v sem o destino do snapshot", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("OnError não chamado")
+		}
+	}
+	if _, err := scheduler.Last(); err == nil {
+		t.Error("Last sem o erro da última execução")
+	}
+}
+
+// waitFor aguarda a condição por até 5 segundos
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condição não atingida em 5s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
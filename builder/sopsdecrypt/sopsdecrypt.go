@@ -0,0 +1,75 @@
+// Package sopsdecrypt decifra parâmetros cujo valor é um documento cifrado pelo SOPS,
+// permitindo guardar no Parameter Store a configuração cifrada pelos fluxos SOPS existentes.
+//
+//	data, err := svc.BuildConfigFromPrefixes(ctx, builder.BuildOptions{
+//		Prefixes:   []string{"/app"},
+//		Decryptors: []builder.Decryptor{sopsdecrypt.New()},
+//	})
+//
+// O pacote é um módulo próprio (github.com/raywall/go-libs-config/builder/sopsdecrypt),
+// para que as dependências do SOPS não sejam exigidas de quem usa apenas o builder.
+package sopsdecrypt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/decrypt"
+	"github.com/raywall/go-libs-config/builder"
+	"gopkg.in/yaml.v3"
+)
+
+// Decryptor decifra documentos SOPS em JSON ou YAML. A chave de dados é obtida pelos
+// provedores registrados nos metadados do documento (ex.: AWS KMS, com as credenciais
+// padrão do ambiente) e a integridade é verificada pelo MAC. O texto plano é emitido
+// como JSON para ser mesclado à árvore. Implementa builder.Decryptor.
+type Decryptor struct{}
+
+// New cria um Decryptor
+func New() *Decryptor {
+	return &Decryptor{}
+}
+
+// Decrypt implementa builder.Decryptor. Valores sem o nó de metadados "sops" são ignorados.
+func (d *Decryptor) Decrypt(ctx context.Context, name, value string) (string, bool, error) {
+	if !isSOPSDocument(value) {
+		return "", false, nil
+	}
+
+	// A API de decifração do SOPS não recebe contexto; respeitamos ao menos o cancelamento prévio
+	if err := ctx.Err(); err != nil {
+		return "", true, err
+	}
+
+	format, from := formats.Yaml, builder.FormatYAML
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		format, from = formats.Json, builder.FormatJSON
+	}
+
+	plaintext, err := decrypt.DataWithFormat([]byte(value), format)
+	if err != nil {
+		return "", true, fmt.Errorf("erro ao decifrar o documento SOPS: %w", err)
+	}
+
+	document, err := builder.Convert(plaintext, from, builder.FormatJSON)
+	if err != nil {
+		return "", true, err
+	}
+	return string(document), true, nil
+}
+
+// isSOPSDocument indica se o valor é um documento com os metadados do SOPS
+func isSOPSDocument(value string) bool {
+	var document map[string]interface{}
+	if err := yaml.Unmarshal([]byte(value), &document); err != nil {
+		return false
+	}
+	metadata, ok := document["sops"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = metadata["mac"]
+	return ok
+}
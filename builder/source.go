@@ -0,0 +1,88 @@
+package builder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source fornece uma árvore de configuração vinda de fora do Parameter Store
+type Source interface {
+	Load(ctx context.Context) (map[string]interface{}, error)
+}
+
+// SourceFunc adapta uma função à interface Source
+type SourceFunc func(ctx context.Context) (map[string]interface{}, error)
+
+// Load implementa Source
+func (f SourceFunc) Load(ctx context.Context) (map[string]interface{}, error) {
+	return f(ctx)
+}
+
+// MapSource fonte com valores fixos, útil para padrões definidos no código
+type MapSource map[string]interface{}
+
+// Load implementa Source retornando uma cópia profunda do mapa
+func (s MapSource) Load(ctx context.Context) (map[string]interface{}, error) {
+	return deepCopyMap(s), nil
+}
+
+// FileSource fonte que lê um arquivo JSON ou YAML (detectado pela extensão)
+type FileSource struct {
+	Path string
+}
+
+// NewFileSource cria uma fonte para o arquivo informado
+func NewFileSource(path string) *FileSource {
+	return &FileSource{Path: path}
+}
+
+// Load implementa Source
+func (s *FileSource) Load(ctx context.Context) (map[string]interface{}, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o arquivo %s: %w", s.Path, err)
+	}
+
+	result := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(s.Path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &result)
+	default:
+		err = json.Unmarshal(data, &result)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar o arquivo %s: %w", s.Path, err)
+	}
+	return result, nil
+}
+
+// deepCopyMap copia recursivamente mapas e listas
+func deepCopyMap(src map[string]interface{}) map[string]interface{} {
+	dest := make(map[string]interface{}, len(src))
+	for key, value := range src {
+		dest[key] = deepCopyValue(value)
+	}
+	return dest
+}
+
+// deepCopyValue copia recursivamente um valor da árvore de configuração
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return deepCopyMap(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = deepCopyValue(item)
+		}
+		return list
+	default:
+		return v
+	}
+}
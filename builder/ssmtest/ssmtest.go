@@ -0,0 +1,451 @@
+// Package ssmtest oferece uma implementação em memória de builder.SSMAPI para testar o
+// carregamento de configurações sem mocks ou LocalStack, com paginação e simulação de
+// throttling. Como no SSM, parâmetros SecureString só têm o valor decifrado com
+// WithDecryption; sem ele, o valor retornado é Ciphertext(valor).
+//
+//	fake := ssmtest.New()
+//	fake.Seed(map[string]string{
+//		"/app/db/host": "localhost",
+//		"/app/db/port": "5432",
+//	})
+//	svc := builder.New(fake)
+package ssmtest
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+)
+
+// DefaultPageSize itens por página quando MaxResults não é informado, como no SSM
+const DefaultPageSize = 10
+
+// ciphertextPrefix identifica os valores SecureString retornados sem WithDecryption
+const ciphertextPrefix = "kms:"
+
+// Parameter parâmetro armazenado no fake
+type Parameter struct {
+	Name         string
+	Value        string
+	Type         types.ParameterType // Padrão: String
+	Description  string
+	Tags         map[string]string
+	Policies     []string // Textos das políticas (ex.: Expiration)
+	Version      int64
+	LastModified time.Time
+}
+
+// Client implementação em memória de builder.SSMAPI, segura para uso concorrente
+type Client struct {
+	mu       sync.Mutex
+	params   map[string]Parameter
+	history  map[string][]Parameter
+	calls    map[string]int
+	throttle func(operation string) bool
+
+	// PageSize limita os itens por página (0 = DefaultPageSize), para exercitar a paginação
+	PageSize int
+	// Region e Account compõem o ARN dos parâmetros
+	Region  string
+	Account string
+}
+
+// New cria um fake vazio
+func New() *Client {
+	return &Client{
+		params:  make(map[string]Parameter),
+		history: make(map[string][]Parameter),
+		calls:   make(map[string]int),
+		Region:  "us-east-1",
+		Account: "123456789012",
+	}
+}
+
+// Seed grava os parâmetros do tipo String informados como nome -> valor
+func (c *Client) Seed(values map[string]string) {
+	for name, value := range values {
+		c.Put(Parameter{Name: name, Value: value})
+	}
+}
+
+// Put grava (ou sobrescreve, incrementando a versão) um parâmetro. Cada gravação é mantida
+// no histórico retornado por GetParameterHistory.
+func (c *Client) Put(param Parameter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if param.Type == "" {
+		param.Type = types.ParameterTypeString
+	}
+	if param.LastModified.IsZero() {
+		param.LastModified = time.Now()
+	}
+	if param.Version == 0 {
+		param.Version = c.params[param.Name].Version + 1
+	}
+	c.params[param.Name] = param
+	c.history[param.Name] = append(c.history[param.Name], param)
+}
+
+// Delete remove um parâmetro e seu histórico
+func (c *Client) Delete(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.params, name)
+	delete(c.history, name)
+}
+
+// Throttle define a função que decide se a chamada da operação (ex.: "GetParametersByPath")
+// deve falhar com ThrottlingException
+func (c *Client) Throttle(fn func(operation string) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.throttle = fn
+}
+
+// ThrottleEvery faz uma a cada n chamadas (de qualquer operação) falhar com ThrottlingException
+func (c *Client) ThrottleEvery(n int) {
+	count := 0
+	c.Throttle(func(string) bool {
+		count++
+		return n > 0 && count%n == 0
+	})
+}
+
+// Calls retorna quantas vezes a operação foi chamada, incluindo as chamadas com throttling
+func (c *Client) Calls(operation string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[operation]
+}
+
+// GetParametersByPath implementa builder.SSMAPI
+func (c *Client) GetParametersByPath(ctx context.Context, input *ssm.GetParametersByPathInput, _ ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.begin(ctx, "GetParametersByPath"); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(aws.ToString(input.Path), "/") {
+		return nil, apiError("ValidationException", "o caminho deve começar com /")
+	}
+
+	base := strings.TrimSuffix(aws.ToString(input.Path), "/") + "/"
+	recursive := aws.ToBool(input.Recursive)
+	var matched []Parameter
+	for _, param := range c.sorted() {
+		relative, ok := strings.CutPrefix(param.Name, base)
+		if !ok || (!recursive && strings.Contains(relative, "/")) {
+			continue
+		}
+		matched = append(matched, param)
+	}
+
+	page, next, err := paginate(matched, input.NextToken, input.MaxResults, c.pageSize(DefaultPageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ssm.GetParametersByPathOutput{NextToken: next}
+	for _, param := range page {
+		output.Parameters = append(output.Parameters, c.parameter(param, aws.ToBool(input.WithDecryption)))
+	}
+	return output, nil
+}
+
+// GetParameters implementa builder.SSMAPI
+func (c *Client) GetParameters(ctx context.Context, input *ssm.GetParametersInput, _ ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.begin(ctx, "GetParameters"); err != nil {
+		return nil, err
+	}
+	if len(input.Names) > 10 {
+		return nil, apiError("ValidationException", "no máximo 10 nomes por chamada")
+	}
+
+	output := &ssm.GetParametersOutput{}
+	for _, name := range input.Names {
+		param, ok := c.params[name]
+		if !ok {
+			output.InvalidParameters = append(output.InvalidParameters, name)
+			continue
+		}
+		output.Parameters = append(output.Parameters, c.parameter(param, aws.ToBool(input.WithDecryption)))
+	}
+	return output, nil
+}
+
+// GetParameter recupera um único parâmetro, como *ssm.Client
+func (c *Client) GetParameter(ctx context.Context, input *ssm.GetParameterInput, _ ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.begin(ctx, "GetParameter"); err != nil {
+		return nil, err
+	}
+
+	param, ok := c.params[aws.ToString(input.Name)]
+	if !ok {
+		return nil, &types.ParameterNotFound{Message: aws.String("parâmetro não encontrado: " + aws.ToString(input.Name))}
+	}
+	parameter := c.parameter(param, aws.ToBool(input.WithDecryption))
+	return &ssm.GetParameterOutput{Parameter: &parameter}, nil
+}
+
+// GetParameterHistory implementa builder.SSMAPI, listando as versões da mais antiga para a
+// mais recente
+func (c *Client) GetParameterHistory(ctx context.Context, input *ssm.GetParameterHistoryInput, _ ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.begin(ctx, "GetParameterHistory"); err != nil {
+		return nil, err
+	}
+
+	versions, ok := c.history[aws.ToString(input.Name)]
+	if !ok {
+		return nil, &types.ParameterNotFound{Message: aws.String("parâmetro não encontrado: " + aws.ToString(input.Name))}
+	}
+
+	page, next, err := paginate(versions, input.NextToken, input.MaxResults, c.pageSize(DefaultPageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ssm.GetParameterHistoryOutput{NextToken: next}
+	for _, param := range page {
+		output.Parameters = append(output.Parameters, types.ParameterHistory{
+			Name:             aws.String(param.Name),
+			Value:            aws.String(value(param, aws.ToBool(input.WithDecryption))),
+			Type:             param.Type,
+			Version:          param.Version,
+			Description:      aws.String(param.Description),
+			LastModifiedDate: aws.Time(param.LastModified),
+			DataType:         aws.String("text"),
+		})
+	}
+	return output, nil
+}
+
+// DescribeParameters implementa builder.SSMAPI. Suporta os filtros Path (Recursive ou
+// OneLevel), Name (Equals ou BeginsWith), Type, tag:<chave> e tag-key.
+func (c *Client) DescribeParameters(ctx context.Context, input *ssm.DescribeParametersInput, _ ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.begin(ctx, "DescribeParameters"); err != nil {
+		return nil, err
+	}
+
+	var matched []Parameter
+	for _, param := range c.sorted() {
+		ok, err := matchesFilters(param, input.ParameterFilters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, param)
+		}
+	}
+
+	page, next, err := paginate(matched, input.NextToken, input.MaxResults, c.pageSize(DefaultPageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ssm.DescribeParametersOutput{NextToken: next}
+	for _, param := range page {
+		metadata := types.ParameterMetadata{
+			Name:             aws.String(param.Name),
+			Type:             param.Type,
+			Version:          param.Version,
+			LastModifiedDate: aws.Time(param.LastModified),
+			ARN:              aws.String(c.arn(param.Name)),
+		}
+		if param.Description != "" {
+			metadata.Description = aws.String(param.Description)
+		}
+		for _, policy := range param.Policies {
+			metadata.Policies = append(metadata.Policies, types.ParameterInlinePolicy{
+				PolicyText:   aws.String(policy),
+				PolicyStatus: aws.String("Pending"),
+			})
+		}
+		output.Parameters = append(output.Parameters, metadata)
+	}
+	return output, nil
+}
+
+// ListTagsForResource implementa builder.SSMAPI
+func (c *Client) ListTagsForResource(ctx context.Context, input *ssm.ListTagsForResourceInput, _ ...func(*ssm.Options)) (*ssm.ListTagsForResourceOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.begin(ctx, "ListTagsForResource"); err != nil {
+		return nil, err
+	}
+
+	param, ok := c.params[aws.ToString(input.ResourceId)]
+	if !ok {
+		return nil, &types.InvalidResourceId{Message: aws.String("recurso não encontrado: " + aws.ToString(input.ResourceId))}
+	}
+
+	keys := make([]string, 0, len(param.Tags))
+	for key := range param.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	output := &ssm.ListTagsForResourceOutput{}
+	for _, key := range keys {
+		output.TagList = append(output.TagList, types.Tag{Key: aws.String(key), Value: aws.String(param.Tags[key])})
+	}
+	return output, nil
+}
+
+// begin registra a chamada e aplica o cancelamento do contexto e o throttling
+func (c *Client) begin(ctx context.Context, operation string) error {
+	c.calls[operation]++
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.throttle != nil && c.throttle(operation) {
+		return apiError("ThrottlingException", "Rate exceeded")
+	}
+	return nil
+}
+
+// sorted retorna os parâmetros ordenados pelo nome
+func (c *Client) sorted() []Parameter {
+	params := make([]Parameter, 0, len(c.params))
+	for _, param := range c.params {
+		params = append(params, param)
+	}
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Name < params[j].Name
+	})
+	return params
+}
+
+// parameter converte o parâmetro armazenado no tipo da API
+func (c *Client) parameter(param Parameter, decrypt bool) types.Parameter {
+	return types.Parameter{
+		Name:             aws.String(param.Name),
+		Value:            aws.String(value(param, decrypt)),
+		Type:             param.Type,
+		Version:          param.Version,
+		LastModifiedDate: aws.Time(param.LastModified),
+		ARN:              aws.String(c.arn(param.Name)),
+		DataType:         aws.String("text"),
+	}
+}
+
+// Ciphertext retorna o valor que o fake devolve para um SecureString sem WithDecryption
+func Ciphertext(value string) string {
+	return ciphertextPrefix + base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+// value retorna o valor do parâmetro, cifrado quando é SecureString e decrypt é falso
+func value(param Parameter, decrypt bool) string {
+	if param.Type == types.ParameterTypeSecureString && !decrypt {
+		return Ciphertext(param.Value)
+	}
+	return param.Value
+}
+
+// arn monta o ARN do parâmetro
+func (c *Client) arn(name string) string {
+	return fmt.Sprintf("arn:aws:ssm:%s:%s:parameter/%s", c.Region, c.Account, strings.TrimPrefix(name, "/"))
+}
+
+// pageSize retorna o tamanho de página configurado ou o padrão
+func (c *Client) pageSize(fallback int) int {
+	if c.PageSize > 0 {
+		return c.PageSize
+	}
+	return fallback
+}
+
+// paginate retorna a página iniciada em token, com no máximo maxResults (ou size) itens
+func paginate(params []Parameter, token *string, maxResults *int32, size int) ([]Parameter, *string, error) {
+	start := 0
+	if token != nil {
+		n, err := strconv.Atoi(*token)
+		if err != nil || n < 0 || n > len(params) {
+			return nil, nil, apiError("InvalidNextToken", "NextToken inválido")
+		}
+		start = n
+	}
+	if maxResults != nil && *maxResults > 0 && int(*maxResults) < size {
+		size = int(*maxResults)
+	}
+
+	end := min(start+size, len(params))
+	var next *string
+	if end < len(params) {
+		next = aws.String(strconv.Itoa(end))
+	}
+	return params[start:end], next, nil
+}
+
+// matchesFilters indica se o parâmetro atende a todos os filtros de DescribeParameters
+func matchesFilters(param Parameter, filters []types.ParameterStringFilter) (bool, error) {
+	for _, filter := range filters {
+		key, option := aws.ToString(filter.Key), aws.ToString(filter.Option)
+		var ok bool
+		switch {
+		case key == "Path":
+			for _, value := range filter.Values {
+				relative, found := strings.CutPrefix(param.Name, strings.TrimSuffix(value, "/")+"/")
+				ok = ok || (found && (option == "Recursive" || !strings.Contains(relative, "/")))
+			}
+		case key == "Name":
+			for _, value := range filter.Values {
+				if option == "BeginsWith" {
+					ok = ok || strings.HasPrefix(param.Name, value)
+				} else {
+					ok = ok || param.Name == value
+				}
+			}
+		case key == "Type":
+			for _, value := range filter.Values {
+				ok = ok || string(param.Type) == value
+			}
+		case key == "tag-key":
+			for _, value := range filter.Values {
+				_, found := param.Tags[value]
+				ok = ok || found
+			}
+		case strings.HasPrefix(key, "tag:"):
+			tag, found := param.Tags[strings.TrimPrefix(key, "tag:")]
+			for _, value := range filter.Values {
+				ok = ok || (found && tag == value)
+			}
+		default:
+			return false, apiError("InvalidFilterKey", "filtro não suportado pelo fake: "+key)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// apiError cria um erro no formato dos erros da API, reconhecido por smithy.APIError
+func apiError(code, message string) error {
+	return &smithy.GenericAPIError{Code: code, Message: message, Fault: smithy.FaultClient}
+}
@@ -0,0 +1,225 @@
+package ssmtest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+func TestSecureStringRequiresDecryption(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Put(ssmtest.Parameter{Name: "/app/password", Value: "s3cr3t", Type: types.ParameterTypeSecureString})
+	ctx := context.Background()
+
+	for _, decrypt := range []bool{false, true} {
+		want := ssmtest.Ciphertext("s3cr3t")
+		if decrypt {
+			want = "s3cr3t"
+		}
+
+		byPath, err := fake.GetParametersByPath(ctx, &ssm.GetParametersByPathInput{Path: aws.String("/app"), WithDecryption: aws.Bool(decrypt)})
+		if err != nil {
+			t.Fatalf("GetParametersByPath: %v", err)
+		}
+		if got := aws.ToString(byPath.Parameters[0].Value); got != want {
+			t.Errorf("GetParametersByPath com WithDecryption=%t = %q, esperado %q", decrypt, got, want)
+		}
+
+		byName, err := fake.GetParameters(ctx, &ssm.GetParametersInput{Names: []string{"/app/password"}, WithDecryption: aws.Bool(decrypt)})
+		if err != nil {
+			t.Fatalf("GetParameters: %v", err)
+		}
+		if got := aws.ToString(byName.Parameters[0].Value); got != want {
+			t.Errorf("GetParameters com WithDecryption=%t = %q, esperado %q", decrypt, got, want)
+		}
+
+		history, err := fake.GetParameterHistory(ctx, &ssm.GetParameterHistoryInput{Name: aws.String("/app/password"), WithDecryption: aws.Bool(decrypt)})
+		if err != nil {
+			t.Fatalf("GetParameterHistory: %v", err)
+		}
+		if got := aws.ToString(history.Parameters[0].Value); got != want {
+			t.Errorf("GetParameterHistory com WithDecryption=%t = %q, esperado %q", decrypt, got, want)
+		}
+	}
+}
+
+// names retorna os nomes dos parâmetros da página
+func names(params []types.Parameter) []string {
+	result := make([]string, 0, len(params))
+	for _, param := range params {
+		result = append(result, aws.ToString(param.Name))
+	}
+	return result
+}
+
+// apiErrorCode retorna o código do smithy.APIError, ou "" quando err não é um erro da API
+func apiErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+func TestGetParametersByPathPagination(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/a": "1", "/app/b": "2", "/app/c": "3", "/app/d": "4", "/app/e": "5"})
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		pageSize   int
+		maxResults *int32
+		want       [][]string
+	}{
+		{name: "DefaultPageSize", want: [][]string{{"/app/a", "/app/b", "/app/c", "/app/d", "/app/e"}}},
+		{name: "PageSize", pageSize: 2, want: [][]string{{"/app/a", "/app/b"}, {"/app/c", "/app/d"}, {"/app/e"}}},
+		{name: "MaxResults menor que PageSize", pageSize: 4, maxResults: aws.Int32(3), want: [][]string{{"/app/a", "/app/b", "/app/c"}, {"/app/d", "/app/e"}}},
+		{name: "MaxResults maior que PageSize", pageSize: 2, maxResults: aws.Int32(10), want: [][]string{{"/app/a", "/app/b"}, {"/app/c", "/app/d"}, {"/app/e"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.PageSize = tt.pageSize
+			var pages [][]string
+			var token *string
+			for {
+				output, err := fake.GetParametersByPath(ctx, &ssm.GetParametersByPathInput{
+					Path:       aws.String("/app"),
+					MaxResults: tt.maxResults,
+					NextToken:  token,
+				})
+				if err != nil {
+					t.Fatalf("GetParametersByPath: %v", err)
+				}
+				pages = append(pages, names(output.Parameters))
+				if output.NextToken == nil {
+					break
+				}
+				token = output.NextToken
+			}
+			if !reflect.DeepEqual(pages, tt.want) {
+				t.Errorf("páginas = %v, esperado %v", pages, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidNextToken(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/a": "1"})
+
+	for _, token := range []string{"abc", "-1", "5"} {
+		_, err := fake.GetParametersByPath(context.Background(), &ssm.GetParametersByPathInput{
+			Path:      aws.String("/app"),
+			NextToken: aws.String(token),
+		})
+		if code := apiErrorCode(err); code != "InvalidNextToken" {
+			t.Errorf("NextToken %q: erro %v, esperado InvalidNextToken", token, err)
+		}
+	}
+}
+
+func TestThrottleEvery(t *testing.T) {
+	fake := ssmtest.New()
+	fake.ThrottleEvery(3)
+	ctx := context.Background()
+
+	var throttled []int
+	for i := 1; i <= 6; i++ {
+		_, err := fake.GetParameters(ctx, &ssm.GetParametersInput{Names: []string{"/app/a"}})
+		if err == nil {
+			continue
+		}
+		if code := apiErrorCode(err); code != "ThrottlingException" {
+			t.Fatalf("chamada %d: erro %v, esperado ThrottlingException", i, err)
+		}
+		throttled = append(throttled, i)
+	}
+
+	if want := []int{3, 6}; !reflect.DeepEqual(throttled, want) {
+		t.Errorf("chamadas com throttling = %v, esperado %v", throttled, want)
+	}
+	if calls := fake.Calls("GetParameters"); calls != 6 {
+		t.Errorf("Calls = %d, esperado 6 (incluindo as chamadas com throttling)", calls)
+	}
+}
+
+func TestThrottleByOperation(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/a": "1"})
+	fake.Throttle(func(operation string) bool { return operation == "DescribeParameters" })
+	ctx := context.Background()
+
+	if _, err := fake.GetParametersByPath(ctx, &ssm.GetParametersByPathInput{Path: aws.String("/app")}); err != nil {
+		t.Errorf("GetParametersByPath: %v", err)
+	}
+	_, err := fake.DescribeParameters(ctx, &ssm.DescribeParametersInput{})
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ThrottlingException" || apiErr.ErrorFault() != smithy.FaultClient {
+		t.Errorf("DescribeParameters: erro %v, esperado ThrottlingException", err)
+	}
+}
+
+func TestDescribeParametersFilters(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Put(ssmtest.Parameter{Name: "/app/db/host", Value: "localhost", Tags: map[string]string{"env": "prod"}})
+	fake.Put(ssmtest.Parameter{Name: "/app/db/password", Value: "s3cr3t", Type: types.ParameterTypeSecureString, Tags: map[string]string{"env": "dev"}})
+	fake.Put(ssmtest.Parameter{Name: "/app/debug", Value: "true", Tags: map[string]string{"owner": "team"}})
+	fake.Put(ssmtest.Parameter{Name: "/other/key", Value: "x", Tags: map[string]string{"env": "prod"}})
+
+	filter := func(key, option string, values ...string) types.ParameterStringFilter {
+		f := types.ParameterStringFilter{Key: aws.String(key), Values: values}
+		if option != "" {
+			f.Option = aws.String(option)
+		}
+		return f
+	}
+
+	tests := []struct {
+		name    string
+		filters []types.ParameterStringFilter
+		want    []string
+	}{
+		{name: "Path OneLevel", filters: []types.ParameterStringFilter{filter("Path", "OneLevel", "/app")}, want: []string{"/app/debug"}},
+		{name: "Path Recursive", filters: []types.ParameterStringFilter{filter("Path", "Recursive", "/app")}, want: []string{"/app/db/host", "/app/db/password", "/app/debug"}},
+		{name: "Name BeginsWith", filters: []types.ParameterStringFilter{filter("Name", "BeginsWith", "/app/db")}, want: []string{"/app/db/host", "/app/db/password"}},
+		{name: "Type", filters: []types.ParameterStringFilter{filter("Type", "", "SecureString")}, want: []string{"/app/db/password"}},
+		{name: "tag", filters: []types.ParameterStringFilter{filter("tag:env", "", "prod")}, want: []string{"/app/db/host", "/other/key"}},
+		{name: "tag-key", filters: []types.ParameterStringFilter{filter("tag-key", "", "owner")}, want: []string{"/app/debug"}},
+		{name: "combinados", filters: []types.ParameterStringFilter{filter("Path", "Recursive", "/app"), filter("tag:env", "", "prod")}, want: []string{"/app/db/host"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := fake.DescribeParameters(context.Background(), &ssm.DescribeParametersInput{
+				ParameterFilters: tt.filters,
+				MaxResults:       aws.Int32(50),
+			})
+			if err != nil {
+				t.Fatalf("DescribeParameters: %v", err)
+			}
+			got := make([]string, 0, len(output.Parameters))
+			for _, item := range output.Parameters {
+				got = append(got, aws.ToString(item.Name))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parâmetros = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+
+	_, err := fake.DescribeParameters(context.Background(), &ssm.DescribeParametersInput{
+		ParameterFilters: []types.ParameterStringFilter{filter("DataType", "", "text")},
+	})
+	if code := apiErrorCode(err); code != "InvalidFilterKey" {
+		t.Errorf("filtro não suportado: erro %v, esperado InvalidFilterKey", err)
+	}
+}
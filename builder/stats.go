@@ -0,0 +1,153 @@
+package builder
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// statsTopN quantidade de itens de LargestValues e DeepestPaths
+const statsTopN = 5
+
+// BuildStats estatísticas de uma construção, entregues a OnStats para monitorar a saúde
+// da árvore de parâmetros (tamanho, profundidade e sobreposição entre prefixos)
+type BuildStats struct {
+	Prefixes       int              // Prefixos montados
+	Parameters     int              // Parâmetros incluídos na configuração
+	Pages          int              // Páginas de GetParametersByPath (prefixos servidos pelo cache não contam)
+	Bytes          int              // Soma do tamanho dos valores
+	OutputBytes    int              // Tamanho do documento gerado (0 em BuildMap)
+	LargestValues  []ParameterSize  // Maiores valores, em ordem decrescente
+	DeepestPaths   []ParameterDepth // Parâmetros mais profundos, em ordem decrescente
+	MergeConflicts []string         // Caminhos sobrescritos por prefixos posteriores (listas concatenadas não contam)
+	Duration       time.Duration
+}
+
+// ParameterSize tamanho do valor de um parâmetro
+type ParameterSize struct {
+	Name  string
+	Bytes int
+}
+
+// ParameterDepth profundidade (número de segmentos) do nome de um parâmetro
+type ParameterDepth struct {
+	Name  string
+	Depth int
+}
+
+// statsCollector acumula as estatísticas durante a construção
+type statsCollector struct {
+	mu        sync.Mutex
+	start     time.Time
+	pages     int
+	conflicts []string
+}
+
+// withStats associa ao contexto um coletor de estatísticas, quando OnStats está definido
+func (b *ConfigBuilder) withStats(ctx context.Context, opts BuildOptions) (context.Context, *statsCollector) {
+	if opts.OnStats == nil {
+		return ctx, nil
+	}
+	stats := &statsCollector{start: time.Now()}
+	return context.WithValue(ctx, statsKey, stats), stats
+}
+
+// statsFrom retorna o coletor de estatísticas do contexto, se houver
+func statsFrom(ctx context.Context) *statsCollector {
+	stats, _ := ctx.Value(statsKey).(*statsCollector)
+	return stats
+}
+
+// countPage registra uma página buscada, se houver coletor no contexto
+func countPage(ctx context.Context) {
+	if stats := statsFrom(ctx); stats != nil {
+		stats.mu.Lock()
+		stats.pages++
+		stats.mu.Unlock()
+	}
+}
+
+// addConflicts registra as chaves de src que sobrescrevem valores de dest na mescla com a
+// estratégia informada. Listas concatenadas por MergeAppend não são conflitos; valores
+// escalares sobrescritos e tipos divergentes são.
+func (s *statsCollector) addConflicts(dest, src map[string]interface{}, path string, strategy MergeStrategy) {
+	for key, value := range src {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+
+		existing, exists := dest[key]
+		if !exists {
+			continue
+		}
+		existingMap, ok1 := existing.(map[string]interface{})
+		valueMap, ok2 := value.(map[string]interface{})
+		if ok1 && ok2 {
+			s.addConflicts(existingMap, valueMap, keyPath, strategy)
+			continue
+		}
+		if strategy == MergeAppend {
+			_, existingList := existing.([]interface{})
+			_, valueList := value.([]interface{})
+			if existingList && valueList {
+				continue
+			}
+		}
+
+		s.mu.Lock()
+		s.conflicts = append(s.conflicts, keyPath)
+		s.mu.Unlock()
+	}
+}
+
+// report monta as estatísticas da montagem e do documento gerado
+func (s *statsCollector) report(asm *assembly, output []byte) BuildStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := BuildStats{
+		Prefixes:       len(asm.fetched),
+		Parameters:     len(asm.params),
+		Pages:          s.pages,
+		OutputBytes:    len(output),
+		LargestValues:  largestValues(asm.params),
+		DeepestPaths:   deepestPaths(asm.params),
+		MergeConflicts: s.conflicts,
+		Duration:       time.Since(s.start),
+	}
+	sort.Strings(stats.MergeConflicts)
+	for _, param := range asm.params {
+		stats.Bytes += len(*param.Value)
+	}
+	return stats
+}
+
+// largestValues retorna os statsTopN maiores valores
+func largestValues(params []types.Parameter) []ParameterSize {
+	sizes := make([]ParameterSize, 0, len(params))
+	for _, param := range params {
+		sizes = append(sizes, ParameterSize{Name: *param.Name, Bytes: len(*param.Value)})
+	}
+	sort.SliceStable(sizes, func(i, j int) bool {
+		return sizes[i].Bytes > sizes[j].Bytes
+	})
+	return sizes[:min(statsTopN, len(sizes))]
+}
+
+// deepestPaths retorna os statsTopN parâmetros com mais segmentos no nome
+func deepestPaths(params []types.Parameter) []ParameterDepth {
+	depths := make([]ParameterDepth, 0, len(params))
+	for _, param := range params {
+		depth := len(strings.Split(strings.Trim(*param.Name, "/"), "/"))
+		depths = append(depths, ParameterDepth{Name: *param.Name, Depth: depth})
+	}
+	sort.SliceStable(depths, func(i, j int) bool {
+		return depths[i].Depth > depths[j].Depth
+	})
+	return depths[:min(statsTopN, len(depths))]
+}
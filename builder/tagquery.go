@@ -0,0 +1,97 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// getParametersBatchSize limite de nomes por chamada de GetParameters
+const getParametersBatchSize = 10
+
+// TagFilter seleciona os parâmetros que possuem a tag Key com um dos valores em Values,
+// ou com qualquer valor quando Values está vazio
+type TagFilter struct {
+	Key    string
+	Values []string
+}
+
+// BuildFromTagQuery constrói a configuração a partir dos parâmetros que atendem a todos os
+// filtros de tags (ex.: service=checkout), em vez de um prefixo de path. Os parâmetros são
+// montados a partir do maior path comum entre eles; Prefixes, PrefixSpecs e MountKeys são
+// ignorados.
+func (b *ConfigBuilder) BuildFromTagQuery(ctx context.Context, filters []TagFilter, opts BuildOptions) ([]byte, error) {
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("%w: nenhum filtro de tags informado", ErrInvalidOptions)
+	}
+
+	params, err := b.getParametersByTags(ctx, filters, opts.WithDecryption)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make([]string, 0, len(params))
+	for _, param := range params {
+		parents = append(parents, path.Dir(*param.Name))
+	}
+
+	opts.Prefixes = []string{b.commonRoot(parents)}
+	opts.PrefixSpecs = nil
+	opts.MountKeys = nil
+	return b.build(ctx, opts, func(ctx context.Context, prefix string) ([]types.Parameter, error) {
+		return b.selectParameters(ctx, params, prefix, opts)
+	})
+}
+
+// getParametersByTags recupera, ordenados pelo nome, os parâmetros que atendem aos filtros de tags
+func (b *ConfigBuilder) getParametersByTags(ctx context.Context, filters []TagFilter, withDecryption bool) ([]types.Parameter, error) {
+	parameterFilters := make([]types.ParameterStringFilter, 0, len(filters))
+	for _, filter := range filters {
+		if len(filter.Values) == 0 {
+			parameterFilters = append(parameterFilters, types.ParameterStringFilter{
+				Key:    aws.String("tag-key"),
+				Values: []string{filter.Key},
+			})
+			continue
+		}
+		parameterFilters = append(parameterFilters, types.ParameterStringFilter{
+			Key:    aws.String("tag:" + filter.Key),
+			Option: aws.String("Equals"),
+			Values: filter.Values,
+		})
+	}
+
+	metadata, err := b.describeParameterMetadata(ctx, parameterFilters)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar parâmetros por tags: %w", err)
+	}
+
+	var params []types.Parameter
+	for start := 0; start < len(metadata); start += getParametersBatchSize {
+		end := min(start+getParametersBatchSize, len(metadata))
+		names := make([]string, 0, end-start)
+		for _, item := range metadata[start:end] {
+			names = append(names, aws.ToString(item.Name))
+		}
+
+		result, err := b.clientFrom(ctx).GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          names,
+			WithDecryption: aws.Bool(withDecryption),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar parâmetros por tags: %w", err)
+		}
+		// Parâmetros removidos entre as duas chamadas aparecem em InvalidParameters e são ignorados
+		params = append(params, result.Parameters...)
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		return *params[i].Name < *params[j].Name
+	})
+	return params, nil
+}
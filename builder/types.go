@@ -1,19 +1,459 @@
 package builder
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 )
 
-// ConfigBuilder - Construtor genérico de configurações
+// ConfigBuilder - Construtor genérico de configurações.
+// É seguro para uso concorrente: uma mesma instância pode ser compartilhada entre
+// goroutines, e todo estado interno é protegido por mu.
 type ConfigBuilder struct {
-	ssmClient *ssm.Client
+	mu        sync.RWMutex
+	ssmClient SSMAPI
+	s3Client  *s3.Client
+	cache     Cache
+	cacheTTL  time.Duration
+
+	// profileClients clientes SSM já criados para cada perfil de credenciais da AWS
+	profileClients map[string]SSMAPI
+
+	// region e account do cliente padrão, informadas por SetClientLocation
+	region  string
+	account string
+
+	// limiter e semaphore limitam a taxa e a concorrência das chamadas ao SSM
+	limiter   *rateLimiter
+	semaphore chan struct{}
 }
 
+// SSMAPI operações do Parameter Store usadas pelo builder. *ssm.Client satisfaz a
+// interface; builder/ssmtest oferece uma implementação em memória para testes.
+type SSMAPI interface {
+	GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
+	GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
+	DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error)
+	ListTagsForResource(ctx context.Context, params *ssm.ListTagsForResourceInput, optFns ...func(*ssm.Options)) (*ssm.ListTagsForResourceOutput, error)
+	GetParameterHistory(ctx context.Context, params *ssm.GetParameterHistoryInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error)
+}
+
+const (
+	// MetadataKey chave do nó de metadados adicionado quando TagsMetadata está habilitado
+	MetadataKey = "_meta"
+	// BuildKey chave do nó de proveniência adicionado quando BuildMetadata está habilitado
+	BuildKey = "_build"
+)
+
 // BuildOptions opções para construção da configuração
 type BuildOptions struct {
-	Prefixes           []string
-	StripPrefix        bool
-	JSONOutput         bool
-	YAMLRules          bool // Nova opção para modo de regras YAML
-	SortByDependencies bool
+	Prefixes            []string // Paths ou ARNs de parâmetros (ex.: "arn:aws:ssm:us-east-1:123456789012:parameter/app")
+	StripPrefix         bool
+	JSONOutput          bool
+	YAMLRules           bool // Nova opção para modo de regras YAML
+	SortByDependencies  bool
+	RootKey             string        // Chave raiz opcional que envolve toda a configuração gerada
+	YAMLComments        bool          // Inclui a descrição dos parâmetros como comentários no YAML
+	TagsMetadata        bool          // Inclui as tags dos parâmetros no nó "_meta"
+	PageTimeout         time.Duration // Tempo limite de cada chamada paginada ao SSM (0 = sem limite)
+	FetchTimeout        time.Duration // Tempo limite total da busca de cada prefixo (0 = sem limite)
+	BestEffort          bool          // Ignora prefixos com falha e retorna o documento parcial
+	MaxDepth            int           // Profundidade máxima dos parâmetros abaixo do prefixo (0 = sem limite)
+	DepthPolicy         DepthPolicy   // Tratamento dos parâmetros que excedem MaxDepth
+	MaxParameters       int           // Limite de parâmetros por prefixo (0 = sem limite)
+	TruncateParameters  bool          // Trunca em MaxParameters com aviso em vez de retornar erro
+	Recursive           *bool         // Busca recursiva nos prefixos (nil = true)
+	RawValues           bool          // Mantém os valores como texto, sem converter JSON
+	WithDecryption      bool          // Decifra os parâmetros SecureString com KMS (padrão: valor cifrado)
+	YAMLAnchors         bool          // Deduplica subárvores repetidas no YAML com âncoras e aliases
+	YAMLMultiDocument   bool          // Emite um documento YAML (---) por chave de primeiro nível
+	SortKeys            bool          // Ordena as chaves do YAML por bytes (o JSON já é emitido ordenado)
+	JSONIndent          string        // Indentação do JSON quando JSONOutput (padrão: dois espaços)
+	JSONPrefix          string        // Prefixo de cada linha do JSON quando JSONOutput
+	JSONNoHTMLEscape    bool          // Não escapa <, > e & nas strings JSON
+	NDJSONOutput        bool          // Emite documentos em formato de array como JSON delimitado por linhas
+	Gzip                bool          // Retorna a saída comprimida em gzip
+	BuildMetadata       bool          // Inclui o nó "_build" com data, prefixos, contagem, versão e hash
+	ExpirationWarning   time.Duration // Avisa sobre parâmetros expirados ou que expiram nesse intervalo
+	ExpandStringLists   bool          // Converte parâmetros StringList em listas
+	CaseInsensitiveKeys bool          // Mescla chaves que diferem apenas em maiúsculas/minúsculas
+	KeyCase             KeyCase       // Forma canônica das chaves com CaseInsensitiveKeys
+	PatchPath           string        // Subcaminho de cada prefixo com documentos JSON Patch (ex.: "patches")
+	MergeStrategy       MergeStrategy // Estratégia de mescla entre os prefixos (padrão: MergeAppend)
+	GraphQLOutput       bool          // Emite o schema montado no formato de introspecção do GraphQL (__schema)
+	CSVSuffix           string        // Converte os parâmetros com esse sufixo (ex.: ".csv") de CSV em lista de objetos
+	EnvOutput           EnvFormat     // Emite a árvore achatada como variáveis de ambiente do Lambda ou do ECS
+	EnvPrefix           string        // Prefixo do nome das variáveis com EnvOutput (ex.: "APP_")
+	JSONCOutput         bool          // Emite JSONC com a descrição e o parâmetro de origem das chaves como comentários
+
+	// KeySanitization política aplicada às chaves com caracteres especiais no formato de saída
+	KeySanitization KeySanitization
+	// KeyReplacement texto que substitui os caracteres especiais com KeySanitizeReplace
+	KeyReplacement string
+
+	// OnWarning recebe os avisos emitidos durante a construção (padrão: o logger de WithLogger
+	// ou log.Printf)
+	OnWarning func(message string)
+
+	// ARNClient retorna o cliente SSM usado pelos prefixos informados como ARN de outra conta
+	// ou região (ex.: com credenciais de AssumeRole). Sem ele, ou quando retorna nil, os
+	// ARNs são convertidos em paths e buscados com o cliente padrão, desde que a região e a
+	// conta do ARN sejam as do cliente padrão (ver SetClientLocation); caso contrário, a
+	// construção falha com ErrForeignARN.
+	ARNClient func(region, account string) (SSMAPI, error)
+
+	// OnPage é chamado após cada página de GetParametersByPath, para acompanhar o progresso
+	// de buscas longas
+	OnPage func(progress PageProgress)
+
+	// OnStats recebe as estatísticas de cada construção concluída, inclusive as parciais
+	OnStats func(stats BuildStats)
+
+	// MountKeys define a chave sob a qual cada prefixo é montado na árvore final
+	// (ex.: "/teste/app/schema" -> "schema"). Prefixos ausentes são mesclados na raiz.
+	MountKeys map[string]string
+
+	// PrefixSpecs prefixos adicionais com opções próprias, que sobrepõem as opções globais
+	PrefixSpecs []PrefixSpec
+
+	// Filter decide se um parâmetro (pelo nome completo) entra na configuração
+	Filter func(name string) bool
+
+	// Transform altera o valor bruto de um parâmetro antes da conversão
+	Transform func(name, value string) (string, error)
+
+	// ListDelimiters sobrepõe o delimitador de expansão de listas para os parâmetros cujo nome
+	// corresponde ao padrão (sintaxe de path.Match, ex.: "/app/hosts/*" -> ";").
+	// Requer ExpandStringLists e também se aplica a parâmetros do tipo String.
+	ListDelimiters map[string]string
+
+	// CSVValues converte de CSV em lista de objetos os parâmetros cujo nome corresponde ao
+	// padrão (sintaxe de path.Match), com o cabeçalho e o separador informados. Tem
+	// precedência sobre CSVSuffix e sobre a expansão de listas.
+	CSVValues map[string]CSVFormat
+
+	// Limits limita o tamanho e o aninhamento dos valores antes da interpretação, para
+	// parâmetros não confiáveis
+	Limits ParseLimits
+
+	// Codecs seleciona a decodificação de cada valor pelo sufixo ou padrão do nome (ex.:
+	// DefaultCodecs). Tem precedência sobre a expansão de listas e RawValues; parâmetros sem
+	// codec seguem a conversão padrão.
+	Codecs *CodecRegistry
+
+	// KeyRemaps move valores entre caminhos da árvore, em ordem, logo após a mescla dos
+	// prefixos (antes dos JSON Patch e das demais etapas)
+	KeyRemaps []KeyRemap
+
+	// Conditions determina a inclusão das chaves cujo caminho corresponde ao padrão (sintaxe
+	// de Config.GetAll, ex.: "features.beta") pelas tags do parâmetro de origem ou por uma
+	// expressão sobre outros valores. Aplicado após os JSON Patch, antes de TypeSchema.
+	Conditions map[string]Condition
+
+	// TypeSchema declara o tipo esperado (ValueString, ValueInt, ValueFloat ou ValueBool) dos
+	// valores cujo caminho corresponde ao padrão (sintaxe de Config.GetAll, ex.:
+	// "services.*.port"). Os valores são convertidos após a mescla e os JSON Patch; valores
+	// incompatíveis interrompem a construção com ErrCoercionFailed.
+	TypeSchema map[string]ValueType
+
+	// Decryptors decifram os valores cifrados fora do Parameter Store, antes de Transform
+	Decryptors []Decryptor
+
+	// Validators validam a configuração montada; qualquer rejeição interrompe a construção
+	Validators []Validator
+}
+
+// PrefixSpec opções específicas de um prefixo. Campos nulos herdam as opções globais.
+type PrefixSpec struct {
+	Path        string
+	Key         string // Chave de montagem do prefixo na árvore final
+	StripPrefix *bool
+	RawValues   *bool
+	Filter      func(name string) bool
+	Transform   func(name, value string) (string, error)
+
+	// Client cliente SSM usado apenas neste prefixo (ex.: credenciais de outra conta)
+	Client SSMAPI
+	// AWSProfile perfil da configuração compartilhada da AWS (~/.aws/config) cujas
+	// credenciais e região são usadas neste prefixo. Não pode ser combinado com Client.
+	AWSProfile string
+}
+
+// PageProgress progresso da busca paginada de um prefixo
+type PageProgress struct {
+	Prefix     string
+	Page       int  // Número da página, a partir de 1
+	Parameters int  // Total de parâmetros obtidos até esta página
+	More       bool // Indica se há mais páginas (NextToken presente)
+}
+
+// OutputSpec descreve uma saída gerada por BuildMany
+type OutputSpec struct {
+	Name    string // Identificador da saída no resultado
+	Options BuildOptions
+}
+
+// ErrInvalidOptions indica uma combinação inválida de BuildOptions
+var ErrInvalidOptions = errors.New("opções de construção inválidas")
+
+// Validate verifica se as opções são consistentes, listando todos os problemas encontrados
+func (o BuildOptions) Validate() error {
+	var problems []error
+
+	if len(o.Prefixes) == 0 && len(o.PrefixSpecs) == 0 {
+		problems = append(problems, errors.New("nenhum prefixo informado em Prefixes ou PrefixSpecs"))
+	}
+	for i, prefix := range o.Prefixes {
+		if _, isARN, err := parseParameterARN(prefix); err != nil {
+			problems = append(problems, fmt.Errorf("prefixo %d: %w", i, err))
+		} else if !isARN && !strings.HasPrefix(prefix, "/") {
+			problems = append(problems, fmt.Errorf("prefixo %d (%q) deve começar com \"/\" ou ser um ARN", i, prefix))
+		}
+	}
+	for i, spec := range o.PrefixSpecs {
+		if _, isARN, err := parseParameterARN(spec.Path); err != nil {
+			problems = append(problems, fmt.Errorf("PrefixSpecs %d: %w", i, err))
+		} else if !isARN && !strings.HasPrefix(spec.Path, "/") {
+			problems = append(problems, fmt.Errorf("PrefixSpecs %d (%q) deve começar com \"/\" ou ser um ARN", i, spec.Path))
+		}
+		if spec.Client != nil && spec.AWSProfile != "" {
+			problems = append(problems, fmt.Errorf("PrefixSpecs %d (%q): Client e AWSProfile são mutuamente exclusivos", i, spec.Path))
+		}
+	}
+	for prefix := range o.MountKeys {
+		if !slices.Contains(o.Prefixes, prefix) {
+			problems = append(problems, fmt.Errorf("MountKeys referencia o prefixo %s, ausente em Prefixes", prefix))
+		}
+	}
+	if o.YAMLRules && o.JSONOutput {
+		problems = append(problems, errors.New("YAMLRules e JSONOutput são mutuamente exclusivos"))
+	}
+	if o.YAMLRules && o.SortByDependencies {
+		problems = append(problems, errors.New("SortByDependencies não é suportado com YAMLRules"))
+	}
+	if o.YAMLComments && !o.YAMLRules {
+		problems = append(problems, errors.New("YAMLComments requer YAMLRules"))
+	}
+	if o.YAMLAnchors && !o.YAMLRules {
+		problems = append(problems, errors.New("YAMLAnchors requer YAMLRules"))
+	}
+	if o.YAMLMultiDocument && !o.YAMLRules {
+		problems = append(problems, errors.New("YAMLMultiDocument requer YAMLRules"))
+	}
+	if o.NDJSONOutput && (o.YAMLRules || o.JSONOutput || o.RootKey != "" || o.TagsMetadata || o.BuildMetadata) {
+		problems = append(problems, errors.New("NDJSONOutput não pode ser combinado com YAMLRules, JSONOutput, RootKey, TagsMetadata ou BuildMetadata"))
+	}
+	if o.GraphQLOutput && (o.YAMLRules || o.NDJSONOutput || o.RootKey != "") {
+		problems = append(problems, errors.New("GraphQLOutput não pode ser combinado com YAMLRules, NDJSONOutput ou RootKey"))
+	}
+	if o.EnvOutput != EnvNone && (o.YAMLRules || o.NDJSONOutput || o.GraphQLOutput) {
+		problems = append(problems, errors.New("EnvOutput não pode ser combinado com YAMLRules, NDJSONOutput ou GraphQLOutput"))
+	}
+	if o.JSONCOutput && (o.YAMLRules || o.NDJSONOutput || o.GraphQLOutput || o.EnvOutput != EnvNone) {
+		problems = append(problems, errors.New("JSONCOutput não pode ser combinado com YAMLRules, NDJSONOutput, GraphQLOutput ou EnvOutput"))
+	}
+	if o.EnvPrefix != "" && o.EnvOutput == EnvNone {
+		problems = append(problems, errors.New("EnvPrefix requer EnvOutput"))
+	}
+	if o.YAMLMultiDocument && o.RootKey != "" {
+		problems = append(problems, errors.New("YAMLMultiDocument não pode ser combinado com RootKey"))
+	}
+	if o.PageTimeout < 0 || o.FetchTimeout < 0 {
+		problems = append(problems, errors.New("PageTimeout e FetchTimeout não podem ser negativos"))
+	}
+	if o.MaxDepth < 0 {
+		problems = append(problems, errors.New("MaxDepth não pode ser negativo"))
+	}
+	if o.MaxParameters < 0 {
+		problems = append(problems, errors.New("MaxParameters não pode ser negativo"))
+	}
+	if o.Limits.MaxValueSize < 0 || o.Limits.MaxNesting < 0 || o.Limits.MaxDocumentSize < 0 {
+		problems = append(problems, errors.New("os limites de Limits não podem ser negativos"))
+	}
+	if len(o.ListDelimiters) > 0 && !o.ExpandStringLists {
+		problems = append(problems, errors.New("ListDelimiters requer ExpandStringLists"))
+	}
+	for pattern, delimiter := range o.ListDelimiters {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Errorf("padrão inválido em ListDelimiters %q: %w", pattern, err))
+		}
+		if delimiter == "" {
+			problems = append(problems, fmt.Errorf("delimitador vazio em ListDelimiters para %q", pattern))
+		}
+	}
+	for pattern := range o.CSVValues {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Errorf("padrão inválido em CSVValues %q: %w", pattern, err))
+		}
+	}
+	if o.YAMLRules && (o.CSVSuffix != "" || len(o.CSVValues) > 0 || o.Codecs != nil) {
+		problems = append(problems, errors.New("CSVSuffix, CSVValues e Codecs não são suportados com YAMLRules"))
+	}
+	for i, remap := range o.KeyRemaps {
+		if remap.From == "" || remap.To == "" || strings.Contains(remap.To, "*") {
+			problems = append(problems, fmt.Errorf("KeyRemaps %d: informe From e To, sem \"*\" em To", i))
+			continue
+		}
+		wildcards := len(wildcardPositions(remap.From))
+		for _, ref := range remapCapture.FindAllStringSubmatch(remap.To, -1) {
+			if n, _ := strconv.Atoi(ref[1]); n < 1 || n > wildcards {
+				problems = append(problems, fmt.Errorf("KeyRemaps %d: %s não corresponde a um \"*\" de %q", i, ref[0], remap.From))
+			}
+		}
+	}
+	for pattern, condition := range o.Conditions {
+		if pattern == "" || (len(condition.Tags) == 0 && condition.When == nil) {
+			problems = append(problems, fmt.Errorf("condição inválida em Conditions para %q: informe o padrão e Tags ou When", pattern))
+		}
+	}
+	for pattern, valueType := range o.TypeSchema {
+		switch valueType {
+		case ValueString, ValueInt, ValueFloat, ValueBool:
+		default:
+			problems = append(problems, fmt.Errorf("tipo %s não suportado em TypeSchema para %q", valueType, pattern))
+		}
+	}
+	if o.KeyReplacement != "" && o.KeySanitization != KeySanitizeReplace {
+		problems = append(problems, errors.New("KeyReplacement requer KeySanitizeReplace"))
+	}
+	for _, r := range o.KeyReplacement {
+		if isSpecialKeyRune(r, o.YAMLRules) {
+			problems = append(problems, fmt.Errorf("KeyReplacement %q contém caracteres especiais", o.KeyReplacement))
+			break
+		}
+	}
+	if o.TruncateParameters && o.MaxParameters == 0 {
+		problems = append(problems, errors.New("TruncateParameters requer MaxParameters"))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidOptions, errors.Join(problems...))
+	}
+	return nil
+}
+
+// ErrTooManyParameters indica que um prefixo excedeu MaxParameters
+var ErrTooManyParameters = errors.New("limite de parâmetros excedido")
+
+// DepthPolicy define o tratamento dos parâmetros que excedem MaxDepth
+type DepthPolicy int
+
+const (
+	// DepthError retorna erro ao encontrar um parâmetro além da profundidade máxima
+	DepthError DepthPolicy = iota
+	// DepthFlatten achata os níveis excedentes em uma única chave separada por "."; nos
+	// caminhos de Config.Get e afins, acesse-a com os pontos escapados (veja EscapeKey)
+	DepthFlatten
+	// DepthSkip ignora os parâmetros além da profundidade máxima
+	DepthSkip
+)
+
+// MergeStrategy define como cada prefixo é mesclado sobre os anteriores
+type MergeStrategy int
+
+const (
+	// MergeAppend mescla mapas recursivamente, concatena listas e substitui os demais valores
+	MergeAppend MergeStrategy = iota
+	// MergeOverride mescla mapas recursivamente e substitui os demais valores, incluindo listas
+	MergeOverride
+	// MergePatch aplica cada prefixo como JSON Merge Patch (RFC 7386): valores nulos
+	// removem as chaves contribuídas pelos prefixos anteriores
+	MergePatch
+)
+
+// String retorna o nome da estratégia
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeOverride:
+		return "MergeOverride"
+	case MergePatch:
+		return "MergePatch"
+	default:
+		return "MergeAppend"
+	}
+}
+
+// TimeoutError indica que a busca de um prefixo excedeu o tempo limite configurado
+type TimeoutError struct {
+	Prefix  string
+	Timeout time.Duration
+	Err     error
+}
+
+// Error implementa a interface error
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("tempo limite de %s excedido ao buscar parâmetros do prefixo %s: %v", e.Timeout, e.Prefix, e.Err)
+}
+
+// Unwrap retorna o erro original
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// PrefixError associa um erro ao prefixo que o originou
+type PrefixError struct {
+	Prefix string
+	Err    error
+}
+
+// Error implementa a interface error
+func (e PrefixError) Error() string {
+	return fmt.Sprintf("prefixo %s: %v", e.Prefix, e.Err)
+}
+
+// Unwrap retorna o erro original
+func (e PrefixError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError indica que a configuração foi construída parcialmente no modo BestEffort
+type PartialError struct {
+	Skipped []PrefixError
+}
+
+// Error implementa a interface error
+func (e *PartialError) Error() string {
+	messages := make([]string, 0, len(e.Skipped))
+	for _, skipped := range e.Skipped {
+		messages = append(messages, skipped.Error())
+	}
+	return fmt.Sprintf("configuração construída parcialmente, %d prefixo(s) ignorado(s): %s", len(e.Skipped), strings.Join(messages, "; "))
+}
+
+// Unwrap retorna os erros de cada prefixo ignorado
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Skipped))
+	for _, skipped := range e.Skipped {
+		errs = append(errs, skipped)
+	}
+	return errs
+}
+
+// ParameterError associa um erro ao parâmetro (e ao caminho relativo) que o originou
+type ParameterError struct {
+	Name string
+	Path string
+	Err  error
+}
+
+// Error implementa a interface error
+func (e *ParameterError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("parâmetro %s: %v", e.Name, e.Err)
+	}
+	return fmt.Sprintf("parâmetro %s (caminho %s): %v", e.Name, e.Path, e.Err)
+}
+
+// Unwrap retorna o erro original
+func (e *ParameterError) Unwrap() error {
+	return e.Err
 }
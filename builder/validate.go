@@ -0,0 +1,38 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrValidationFailed indica que a configuração montada foi rejeitada por um Validator
+var ErrValidationFailed = errors.New("configuração rejeitada na validação")
+
+// Validator valida a configuração montada, antes de RootKey e dos nós de metadados.
+// Integrações como esquemas CUE ficam em subpacotes (ex.: builder/cueschema).
+type Validator interface {
+	Validate(ctx context.Context, config map[string]interface{}) error
+}
+
+// ValidatorFunc adapta uma função à interface Validator
+type ValidatorFunc func(ctx context.Context, config map[string]interface{}) error
+
+// Validate implementa a interface Validator
+func (f ValidatorFunc) Validate(ctx context.Context, config map[string]interface{}) error {
+	return f(ctx, config)
+}
+
+// validate executa todos os validadores e reúne os problemas encontrados
+func (b *ConfigBuilder) validate(ctx context.Context, config map[string]interface{}, validators []Validator) error {
+	var errs []error
+	for _, validator := range validators {
+		if err := validator.Validate(ctx, config); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidationFailed, errors.Join(errs...))
+	}
+	return nil
+}
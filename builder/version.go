@@ -0,0 +1,29 @@
+package builder
+
+import "runtime/debug"
+
+// modulePath caminho do módulo usado para identificar a versão da biblioteca
+const modulePath = "github.com/raywall/go-libs-config"
+
+// Version retorna a versão da biblioteca registrada no binário, ou "(devel)" quando indisponível
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+
+	return "(devel)"
+}
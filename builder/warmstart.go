@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// warmStartEntry conteúdo do arquivo de BuildWarmStart
+type warmStartEntry struct {
+	Stamp     string    `json:"stamp"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      []byte    `json:"data"`
+}
+
+// BuildWarmStart constrói a configuração reaproveitando o documento gravado em path por
+// uma construção anterior no mesmo ambiente de execução (ex.: "/tmp/config.json" no Lambda,
+// ou um caminho compartilhado com uma extensão). Antes do reuso, as versões dos parâmetros
+// são conferidas com DescribeParameters, sem buscar nem decifrar os valores; se alguma
+// mudou, a configuração é reconstruída e o arquivo substituído.
+//
+// O arquivo só é reaproveitado com as mesmas opções (ver optionsFingerprint). Funções,
+// clientes, Codecs e Decryptors não são comparáveis: use um path diferente quando eles
+// mudarem. Falhas ao gravar o arquivo são reportadas como aviso; documentos parciais
+// (BestEffort) não são gravados.
+func (b *ConfigBuilder) BuildWarmStart(ctx context.Context, opts BuildOptions, path string) ([]byte, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	stamp, err := b.versionStamp(ctx, opts)
+	if err != nil {
+		return nil, b.annotate(ctx, err)
+	}
+
+	if data, err := os.ReadFile(path); err == nil {
+		var entry warmStartEntry
+		if json.Unmarshal(data, &entry) == nil && entry.Stamp == stamp {
+			return entry.Data, nil
+		}
+	}
+
+	data, err := b.BuildConfigFromPrefixes(ctx, opts)
+	if err != nil {
+		return data, err
+	}
+
+	entry, err := json.Marshal(warmStartEntry{Stamp: stamp, CreatedAt: time.Now().UTC(), Data: data})
+	if err == nil {
+		err = writeFileAtomic(path, entry, 0o600)
+	}
+	if err != nil {
+		b.warn(ctx, opts, "erro ao gravar o arquivo de warm start %s: %v", path, err)
+	}
+	return data, nil
+}
+
+// versionStamp resume as opções e o nome e a versão de todos os parâmetros dos prefixos
+func (b *ConfigBuilder) versionStamp(ctx context.Context, opts BuildOptions) (string, error) {
+	h := sha256.New()
+	fmt.Fprintln(h, b.optionsFingerprint(opts))
+	for _, spec := range b.resolvePrefixes(opts) {
+		prefixCtx, err := b.withPrefixClient(ctx, spec, opts)
+		if err != nil {
+			return "", err
+		}
+		metadata, err := b.describeParameterMetadata(prefixCtx, b.pathFilter(spec.path))
+		if err != nil {
+			return "", fmt.Errorf("erro ao conferir as versões do prefixo %s: %w", spec.path, err)
+		}
+
+		versions := make([]string, 0, len(metadata))
+		for _, item := range metadata {
+			versions = append(versions, fmt.Sprintf("%s@%d", aws.ToString(item.Name), item.Version))
+		}
+		sort.Strings(versions)
+
+		fmt.Fprintf(h, "%s|%s\n", b.clientScope(prefixCtx), spec.path)
+		for _, version := range versions {
+			fmt.Fprintln(h, version)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// optionsFingerprint descreve as opções que alteram o documento gerado. Funções, clientes,
+// Codecs, Decryptors e Validators não são comparáveis: apenas a presença deles é incluída.
+func (b *ConfigBuilder) optionsFingerprint(opts BuildOptions) string {
+	recursive := opts.Recursive == nil || *opts.Recursive
+	specs := b.resolvePrefixes(opts)
+	conditions := opts.Conditions
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "recursive=%t|filter=%t|transform=%t|codecs=%t|decryptors=%d\n",
+		recursive, opts.Filter != nil, opts.Transform != nil, opts.Codecs != nil, len(opts.Decryptors))
+	for _, spec := range specs {
+		fmt.Fprintf(&sb, "prefix=%s|key=%s|strip=%t|raw=%t|profile=%s|filter=%t|transform=%t\n",
+			spec.path, spec.key, spec.stripPrefix, spec.rawValues, spec.awsProfile, spec.filter != nil, spec.transform != nil)
+	}
+	patterns := make([]string, 0, len(conditions))
+	for pattern := range conditions {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		fmt.Fprintf(&sb, "condition=%s|tags=%v|when=%t\n", pattern, conditions[pattern].Tags, conditions[pattern].When != nil)
+	}
+
+	// Os campos restantes são valores simples, mapas (impressos em ordem) e listas
+	opts.Prefixes, opts.PrefixSpecs, opts.MountKeys, opts.Recursive, opts.Conditions = nil, nil, nil, nil, nil
+	opts.Filter, opts.Transform, opts.Codecs, opts.Decryptors, opts.Validators = nil, nil, nil, nil, nil
+	opts.OnWarning, opts.ARNClient, opts.OnPage, opts.OnStats = nil, nil, nil, nil
+	fmt.Fprintf(&sb, "%+v", opts)
+	return sb.String()
+}
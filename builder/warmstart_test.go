@@ -0,0 +1,152 @@
+package builder_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/raywall/go-libs-config/builder"
+	"github.com/raywall/go-libs-config/builder/ssmtest"
+)
+
+// mustReadFile lê o arquivo ou interrompe o teste
+func mustReadFile(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	return data
+}
+
+func TestBuildWarmStart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "config.json")
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/db/host": "localhost", "/app/debug": "false"})
+	opts := builder.BuildOptions{Prefixes: []string{"/app"}, StripPrefix: true}
+
+	build := func(opts builder.BuildOptions) map[string]interface{} {
+		t.Helper()
+		data, err := builder.New(fake).BuildWarmStart(ctx, opts, path)
+		if err != nil {
+			t.Fatalf("BuildWarmStart: %v", err)
+		}
+		return decode(t, data)
+	}
+
+	tests := []struct {
+		name   string
+		change func()
+		opts   builder.BuildOptions
+		want   map[string]interface{}
+		builds int
+	}{
+		{
+			name:   "primeira construção",
+			opts:   opts,
+			want:   map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}, "debug": false},
+			builds: 1,
+		},
+		{
+			name:   "versões inalteradas",
+			opts:   opts,
+			want:   map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}, "debug": false},
+			builds: 0,
+		},
+		{
+			name:   "parâmetro alterado",
+			change: func() { fake.Put(ssmtest.Parameter{Name: "/app/debug", Value: "true"}) },
+			opts:   opts,
+			want:   map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}, "debug": true},
+			builds: 1,
+		},
+		{
+			name:   "parâmetro criado",
+			change: func() { fake.Put(ssmtest.Parameter{Name: "/app/cache/ttl", Value: "300"}) },
+			opts:   opts,
+			want: map[string]interface{}{
+				"db": map[string]interface{}{"host": "localhost"}, "cache": map[string]interface{}{"ttl": float64(300)}, "debug": true,
+			},
+			builds: 1,
+		},
+		{
+			name:   "parâmetro removido",
+			change: func() { fake.Delete("/app/cache/ttl") },
+			opts:   opts,
+			want:   map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}, "debug": true},
+			builds: 1,
+		},
+		{
+			name:   "opções alteradas",
+			opts:   builder.BuildOptions{Prefixes: []string{"/app"}, StripPrefix: true, RawValues: true},
+			want:   map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}, "debug": "true"},
+			builds: 1,
+		},
+		{
+			name:   "opções restauradas",
+			opts:   opts,
+			want:   map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}, "debug": true},
+			builds: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.change != nil {
+				tt.change()
+			}
+			before := fake.Calls("GetParametersByPath")
+			if got := build(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("configuração = %v, esperado %v", got, tt.want)
+			}
+			if builds := fake.Calls("GetParametersByPath") - before; builds != tt.builds {
+				t.Errorf("GetParametersByPath chamado %d vezes, esperado %d", builds, tt.builds)
+			}
+		})
+	}
+}
+
+func TestBuildWarmStartInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte("não é JSON"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/db/host": "localhost", "/app/debug": "false"})
+
+	data, err := builder.New(fake).BuildWarmStart(context.Background(), builder.BuildOptions{Prefixes: []string{"/app"}, StripPrefix: true}, path)
+	if err != nil {
+		t.Fatalf("BuildWarmStart: %v", err)
+	}
+	want := map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}, "debug": false}
+	if got := decode(t, data); !reflect.DeepEqual(got, want) {
+		t.Errorf("configuração = %v, esperado %v", got, want)
+	}
+
+	// O arquivo inválido é substituído por uma entrada com o documento construído
+	if entry := decode(t, mustReadFile(t, path)); entry["stamp"] == "" || entry["data"] == nil {
+		t.Errorf("arquivo de warm start não regravado: %v", entry)
+	}
+}
+
+func TestBuildWarmStartWriteFailure(t *testing.T) {
+	fake := ssmtest.New()
+	fake.Seed(map[string]string{"/app/db/host": "localhost", "/app/debug": "false"})
+
+	var warnings []string
+	path := filepath.Join(t.TempDir(), "missing", "config.json")
+	_, err := builder.New(fake).BuildWarmStart(context.Background(), builder.BuildOptions{
+		Prefixes:    []string{"/app"},
+		StripPrefix: true,
+		OnWarning:   func(msg string) { warnings = append(warnings, msg) },
+	}, path)
+	if err != nil {
+		t.Fatalf("BuildWarmStart: %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("avisos = %v, esperado um aviso de gravação", warnings)
+	}
+}
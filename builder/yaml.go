@@ -0,0 +1,115 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// anchorNameSanitizer remove caracteres não permitidos em nomes de âncora
+var anchorNameSanitizer = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// anchorState estado da deduplicação de subárvores YAML
+type anchorState struct {
+	hashes  map[*yaml.Node]string
+	counts  map[string]int
+	first   map[string]*yaml.Node
+	names   map[string]bool
+	pending map[*yaml.Node]string
+}
+
+// deduplicateYAML substitui subárvores (mapas e listas) repetidas por aliases para a primeira
+// ocorrência, que recebe uma âncora nomeada a partir da chave onde aparece
+func (b *ConfigBuilder) deduplicateYAML(root *yaml.Node) {
+	state := &anchorState{
+		hashes:  make(map[*yaml.Node]string),
+		counts:  make(map[string]int),
+		first:   make(map[string]*yaml.Node),
+		names:   make(map[string]bool),
+		pending: make(map[*yaml.Node]string),
+	}
+
+	b.hashYAMLNode(root, state)
+	b.replaceDuplicates(root, "root", state)
+}
+
+// hashYAMLNode calcula o hash estrutural de cada nó e conta as ocorrências de mapas e listas
+func (b *ConfigBuilder) hashYAMLNode(node *yaml.Node, state *anchorState) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d|%s|%s|", node.Kind, node.Tag, node.Value)
+	for _, child := range node.Content {
+		fmt.Fprintf(h, "%s|", b.hashYAMLNode(child, state))
+	}
+
+	hash := hex.EncodeToString(h.Sum(nil))
+	state.hashes[node] = hash
+	if (node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode) && len(node.Content) > 0 {
+		state.counts[hash]++
+	}
+	return hash
+}
+
+// replaceDuplicates percorre o documento em ordem, trocando repetições por aliases
+func (b *ConfigBuilder) replaceDuplicates(node *yaml.Node, name string, state *anchorState) {
+	hash := state.hashes[node]
+	if state.counts[hash] > 1 {
+		if first, ok := state.first[hash]; ok {
+			if first.Anchor == "" {
+				first.Anchor = b.anchorName(state.pending[first], state)
+			}
+			*node = yaml.Node{Kind: yaml.AliasNode, Alias: first, Value: first.Anchor}
+			return
+		}
+		state.first[hash] = node
+		state.pending[node] = name
+	}
+
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			b.replaceDuplicates(node.Content[i+1], node.Content[i].Value, state)
+		}
+		return
+	}
+	for _, child := range node.Content {
+		b.replaceDuplicates(child, name, state)
+	}
+}
+
+// anchorName gera um nome de âncora único a partir do nome da chave
+func (b *ConfigBuilder) anchorName(name string, state *anchorState) string {
+	base := anchorNameSanitizer.ReplaceAllString(name, "_")
+	if base == "" {
+		base = "anchor"
+	}
+
+	anchor := base
+	for i := 2; state.names[anchor]; i++ {
+		anchor = fmt.Sprintf("%s_%d", base, i)
+	}
+	state.names[anchor] = true
+	return anchor
+}
+
+// sortYAMLKeys ordena alfabeticamente (por bytes) as chaves de todos os mapeamentos
+func (b *ConfigBuilder) sortYAMLKeys(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+		}
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return pairs[i][0].Value < pairs[j][0].Value
+		})
+		for i, pair := range pairs {
+			node.Content[2*i], node.Content[2*i+1] = pair[0], pair[1]
+		}
+	}
+
+	for _, child := range node.Content {
+		b.sortYAMLKeys(child)
+	}
+}